@@ -70,7 +70,7 @@ func main() {
 		logger.Fatalf("error setting up permissions %s", err)
 	}
 
-	err = cnb.SetupPlatformEnvVars("/platform", *platformEnvVars)
+	err = cnb.SetupPlatformEnvVars("/platform", *platformEnvVars, os.LookupEnv)
 	if err != nil {
 		logger.Fatalf("error setting up platform env vars %s", err)
 	}
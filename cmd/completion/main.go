@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+
+	"github.com/pivotal/kpack/pkg/cnb"
+	"github.com/pivotal/kpack/pkg/registry"
+)
+
+const (
+	terminationMessagePath = "/dev/termination-log"
+	retrieveAttempts       = 3
+)
+
+var (
+	imageTag  = flag.String("imageTag", os.Getenv("IMAGE_TAG"), "tag of image that was created by the lifecycle")
+	runImage  = flag.String("runImage", os.Getenv("RUN_IMAGE"), "run image the lifecycle exported the image with")
+	stackID   = flag.String("stackID", os.Getenv("STACK_ID"), "id of the stack the image was built on")
+	groupPath = flag.String("group", "/layers/group.toml", "buildpack group selected by the lifecycle detector")
+)
+
+func main() {
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "completion:", log.Lshortfile)
+
+	metadataRetriever := &cnb.RemoteMetadataRetriever{
+		RemoteImageFactory: &registry.ImageFactory{
+			KeychainFactory: keychainFactory{authn.DefaultKeychain},
+		},
+	}
+	imageRef := registry.NewNoAuthImageRef(*imageTag)
+
+	// Rebases do not run the detector. When the group cannot be read the buildpacks are read from the image instead.
+	buildpacks, err := cnb.ReadBuildpackGroup(*groupPath)
+	if err != nil && !os.IsNotExist(err) {
+		logger.Printf("unable to read buildpack group %s, reading the buildpacks from the image: %s", *groupPath, err)
+	}
+
+	var builtImage cnb.BuiltImage
+	if err == nil && len(buildpacks) > 0 && *runImage != "" {
+		// The lifecycle v0.4 exporter does not write the digest it pushed, so only the manifest is fetched.
+		builtImage = cnb.BuiltImage{
+			CompletedAt:       time.Now().UTC(),
+			BuildpackMetadata: buildpacks,
+			Stack: cnb.BuiltImageStack{
+				RunImage: *runImage,
+				ID:       *stackID,
+			},
+		}
+		err = retry(func() error {
+			var err error
+			builtImage.Identifier, err = metadataRetriever.GetBuiltImageIdentifier(imageRef)
+			return err
+		})
+	} else {
+		err = retry(func() error {
+			var err error
+			builtImage, err = metadataRetriever.GetBuiltImage(imageRef)
+			return err
+		})
+	}
+
+	if err != nil {
+		// The build succeeded, report it without an identifier so the controller retrieves the image instead.
+		logger.Printf("unable to retrieve built image %s, the controller will retry: %s", *imageTag, err)
+		builtImage.Identifier = ""
+	}
+
+	result, err := json.Marshal(builtImage)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(terminationMessagePath, result, 0644)
+	if err != nil {
+		logger.Fatalf("error writing build result %s", err)
+	}
+}
+
+func retry(fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= retrieveAttempts; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+	return err
+}
+
+type keychainFactory struct {
+	keychain authn.Keychain
+}
+
+func (k keychainFactory) KeychainForImageRef(registry.ImageRef) authn.Keychain {
+	return k.keychain
+}
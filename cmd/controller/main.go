@@ -1,25 +1,43 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
+	"net/http"
 	"os"
 	"sync"
 	"time"
 
+	"github.com/knative/pkg/configmap"
+	"github.com/knative/pkg/controller"
+	"github.com/knative/pkg/signals"
+	"github.com/knative/pkg/system"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	"github.com/pivotal/kpack/pkg/blob"
 	"github.com/pivotal/kpack/pkg/buildpod"
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
+	buildscheme "github.com/pivotal/kpack/pkg/client/clientset/versioned/scheme"
 	"github.com/pivotal/kpack/pkg/client/informers/externalversions"
 	"github.com/pivotal/kpack/pkg/cnb"
+	"github.com/pivotal/kpack/pkg/config"
 	"github.com/pivotal/kpack/pkg/git"
+	"github.com/pivotal/kpack/pkg/gitwebhook"
+	"github.com/pivotal/kpack/pkg/health"
+	"github.com/pivotal/kpack/pkg/leaderelection"
+	"github.com/pivotal/kpack/pkg/metrics"
 	"github.com/pivotal/kpack/pkg/reconciler"
 	"github.com/pivotal/kpack/pkg/reconciler/v1alpha1/build"
 	"github.com/pivotal/kpack/pkg/reconciler/v1alpha1/builder"
@@ -30,9 +48,7 @@ import (
 	"github.com/pivotal/kpack/pkg/secret"
 )
 
-const (
-	routinesPerController = 2
-)
+const leaseName = "kpack-controller"
 
 var (
 	kubeconfig = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
@@ -41,12 +57,25 @@ var (
 	buildInitImage  = flag.String("build-init-image", os.Getenv("BUILD_INIT_IMAGE"), "The image used to initialize a build")
 	sourceInitImage = flag.String("source-init-image", os.Getenv("SOURCE_INIT_IMAGE"), "The image used to fetch the app source")
 	credInitImage   = flag.String("cred-init-image", os.Getenv("CRED_INIT_IMAGE"), "The image used to setup build credentials")
-	nopImage        = flag.String("nop-image", os.Getenv("NOP_IMAGE"), "The image used to finish a build")
+	completionImage = flag.String("completion-image", os.Getenv("COMPLETION_IMAGE"), "The image used to report the result of a build")
+
+	gitWebhookAddress = flag.String("git-webhook-address", ":8080", "The address to receive git push webhooks on")
+	gitWebhookSecret  = flag.String("git-webhook-secret", os.Getenv("GIT_WEBHOOK_SECRET"), "The secret used to verify git push webhooks. Git push webhooks are disabled when empty")
+
+	metricsAddress = flag.String("metrics-address", ":9090", "The address to serve prometheus metrics on")
+	healthAddress  = flag.String("health-address", ":8081", "The address to serve the leader and informer health on")
+
+	leaseDuration = flag.Duration("leader-election-lease-duration", 15*time.Second, "The duration that non-leader replicas wait before attempting to take over an unrenewed lease")
+	renewDeadline = flag.Duration("leader-election-renew-deadline", 10*time.Second, "The duration that the leader retries renewing its lease before giving up leadership")
+	retryPeriod   = flag.Duration("leader-election-retry-period", 2*time.Second, "The duration between attempts to acquire or renew the lease")
 )
 
 func main() {
 	flag.Parse()
-	devLogger, err := zap.NewDevelopment()
+	logLevel := zap.NewAtomicLevel()
+	loggerConfig := zap.NewDevelopmentConfig()
+	loggerConfig.Level = logLevel
+	devLogger, err := loggerConfig.Build()
 	if err != nil {
 		log.Fatalf("Couldn't create logger: %s", err)
 	}
@@ -67,29 +96,55 @@ func main() {
 		log.Fatalf("could not get kubernetes client: %s", err.Error())
 	}
 
+	err = buildscheme.AddToScheme(scheme.Scheme)
+	if err != nil {
+		logger.Fatalf("could not register build scheme: %s", err.Error())
+	}
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logger.Named("event-broadcaster").Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: k8sClient.CoreV1().Events("")})
+
+	stopChan := signals.SetupSignalHandler()
+
+	configStore := config.NewStore(logger, config.Default())
+	configStore.OnChange(func(c *config.Config) {
+		logLevel.SetLevel(c.LogLevel)
+	})
+
+	configMapWatcher := configmap.NewInformedWatcher(k8sClient, system.Namespace())
+	configMapWatcher.Watch(config.ConfigName, configStore.OnConfigChanged)
+	err = configMapWatcher.Start(stopChan)
+	if err != nil {
+		logger.Fatalf("could not watch %s config map: %s", config.ConfigName, err.Error())
+	}
+
 	options := reconciler.Options{
-		Logger:                  logger,
-		Client:                  client,
-		ResyncPeriod:            10 * time.Hour,
-		SourcePollingFrequency:  1 * time.Minute,
-		BuilderPollingFrequency: 1 * time.Minute,
+		Logger:      logger,
+		Recorder:    eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "kpack-controller"}),
+		Client:      client,
+		ConfigStore: configStore,
 	}
 
-	informerFactory := externalversions.NewSharedInformerFactory(client, options.ResyncPeriod)
+	resyncPeriod := configStore.Load().ResyncPeriod
+
+	informerFactory := externalversions.NewSharedInformerFactory(client, resyncPeriod)
 	buildInformer := informerFactory.Build().V1alpha1().Builds()
 	imageInformer := informerFactory.Build().V1alpha1().Images()
 	builderInformer := informerFactory.Build().V1alpha1().Builders()
 	clusterBuilderInformer := informerFactory.Build().V1alpha1().ClusterBuilders()
 	sourceResolverInformer := informerFactory.Build().V1alpha1().SourceResolvers()
 
-	k8sInformerFactory := informers.NewSharedInformerFactory(k8sClient, options.ResyncPeriod)
+	k8sInformerFactory := informers.NewSharedInformerFactory(k8sClient, resyncPeriod)
 	pvcInformer := k8sInformerFactory.Core().V1().PersistentVolumeClaims()
 	podInformer := k8sInformerFactory.Core().V1().Pods()
 
+	remoteImageFactory := &registry.ImageFactory{
+		KeychainFactory: secret.NewSecretKeychainFactory(k8sClient),
+	}
+
 	metadataRetriever := &cnb.RemoteMetadataRetriever{
-		RemoteImageFactory: &registry.ImageFactory{
-			KeychainFactory: secret.NewSecretKeychainFactory(k8sClient),
-		},
+		RemoteImageFactory: remoteImageFactory,
 	}
 
 	buildpodGenerator := &buildpod.Generator{
@@ -97,14 +152,19 @@ func main() {
 			BuildInitImage:  *buildInitImage,
 			SourceInitImage: *sourceInitImage,
 			CredsInitImage:  *credInitImage,
-			NopImage:        *nopImage,
+			CompletionImage: *completionImage,
 		},
 		K8sClient: k8sClient,
 	}
 
 	gitResolver := git.NewResolver(k8sClient)
-	blobResolver := &blob.Resolver{}
-	registryResolver := &registry.Resolver{}
+	blobResolver := &blob.Resolver{
+		Client:   &http.Client{Timeout: 30 * time.Second},
+		Keychain: blob.NewK8sBlobKeychain(k8sClient),
+	}
+	registryResolver := &registry.Resolver{RemoteImageFactory: remoteImageFactory}
+
+	metrics.RegisterWorkqueueMetrics()
 
 	buildController := build.NewController(options, k8sClient, buildInformer, podInformer, metadataRetriever, buildpodGenerator)
 	imageController := image.NewController(options, k8sClient, imageInformer, buildInformer, builderInformer, clusterBuilderInformer, sourceResolverInformer, pvcInformer)
@@ -112,33 +172,117 @@ func main() {
 	clusterBuilderController := clusterbuilder.NewController(options, clusterBuilderInformer, metadataRetriever)
 	sourceResolverController := sourceresolver.NewController(options, sourceResolverInformer, gitResolver, blobResolver, registryResolver)
 
-	stopChan := make(chan struct{})
+	limitRoutines(configStore, imageController, 1)
+	limitRoutines(configStore, buildController, 1)
+	limitRoutines(configStore, builderController, 1)
+	limitRoutines(configStore, clusterBuilderController, 1)
+	limitRoutines(configStore, sourceResolverController, 2)
+
 	informerFactory.Start(stopChan)
 	k8sInformerFactory.Start(stopChan)
 
-	cache.WaitForCacheSync(stopChan, buildInformer.Informer().HasSynced)
-	cache.WaitForCacheSync(stopChan, imageInformer.Informer().HasSynced)
-	cache.WaitForCacheSync(stopChan, builderInformer.Informer().HasSynced)
-	cache.WaitForCacheSync(stopChan, clusterBuilderInformer.Informer().HasSynced)
-	cache.WaitForCacheSync(stopChan, sourceResolverInformer.Informer().HasSynced)
-	cache.WaitForCacheSync(stopChan, pvcInformer.Informer().HasSynced)
-	cache.WaitForCacheSync(stopChan, podInformer.Informer().HasSynced)
+	healthChecker := &health.Checker{}
 
-	err = runGroup(
-		func(done <-chan struct{}) error {
-			return imageController.Run(routinesPerController, done)
+	healthServer := &http.Server{
+		Addr:    *healthAddress,
+		Handler: healthChecker.Handler(),
+	}
+
+	gitWebhookServer := &http.Server{
+		Addr: *gitWebhookAddress,
+		Handler: &gitwebhook.Handler{
+			Logger:               logger,
+			Secret:               []byte(*gitWebhookSecret),
+			SourceResolverLister: sourceResolverInformer.Lister(),
+			Enqueue:              sourceResolverController.Enqueue,
+		},
+	}
+
+	metricsServer := &http.Server{
+		Addr:    *metricsAddress,
+		Handler: metrics.Handler(),
+	}
+
+	leaseLock := &leaderelection.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      leaseName,
+			Namespace: system.Namespace(),
 		},
+		Client: k8sClient.CoordinationV1beta1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity:      identity(),
+			EventRecorder: options.Recorder,
+		},
+	}
+
+	leaderLabeler := &leaderelection.LeaderLabeler{
+		Client:    k8sClient.CoreV1(),
+		Namespace: system.Namespace(),
+		PodName:   os.Getenv("POD_NAME"),
+	}
+
+	err = runGroup(
+		untilClosed(stopChan),
 		func(done <-chan struct{}) error {
-			return buildController.Run(routinesPerController, done)
+			synced := cache.WaitForCacheSync(done,
+				buildInformer.Informer().HasSynced,
+				imageInformer.Informer().HasSynced,
+				builderInformer.Informer().HasSynced,
+				clusterBuilderInformer.Informer().HasSynced,
+				sourceResolverInformer.Informer().HasSynced,
+				pvcInformer.Informer().HasSynced,
+				podInformer.Informer().HasSynced,
+			)
+			if !synced {
+				return nil
+			}
+			healthChecker.SetSynced(true)
+
+			return leaderelection.Run(done, leaderelection.Config{
+				Lock:          leaseLock,
+				LeaseDuration: *leaseDuration,
+				RenewDeadline: *renewDeadline,
+				RetryPeriod:   *retryPeriod,
+			}, func(leading <-chan struct{}) error {
+				logger.Infof("Acquired lease %s as %s", leaseLock.Describe(), leaseLock.Identity())
+				healthChecker.SetLeader(true)
+				defer healthChecker.SetLeader(false)
+
+				labelLeader(logger, leaderLabeler, true)
+				defer labelLeader(logger, leaderLabeler, false)
+
+				return runGroup(
+					untilClosed(leading),
+					func(done <-chan struct{}) error {
+						return imageController.Run(config.MaxRoutinesPerController, done)
+					},
+					func(done <-chan struct{}) error {
+						return buildController.Run(config.MaxRoutinesPerController, done)
+					},
+					func(done <-chan struct{}) error {
+						return builderController.Run(config.MaxRoutinesPerController, done)
+					},
+					func(done <-chan struct{}) error {
+						return clusterBuilderController.Run(config.MaxRoutinesPerController, done)
+					},
+					func(done <-chan struct{}) error {
+						return sourceResolverController.Run(2*config.MaxRoutinesPerController, done)
+					},
+				)
+			})
 		},
 		func(done <-chan struct{}) error {
-			return builderController.Run(routinesPerController, done)
+			if *gitWebhookSecret == "" {
+				<-done
+				return nil
+			}
+			return runServer(gitWebhookServer, done)
 		},
 		func(done <-chan struct{}) error {
-			return clusterBuilderController.Run(routinesPerController, done)
+			return runServer(metricsServer, done)
 		},
 		func(done <-chan struct{}) error {
-			return sourceResolverController.Run(2*routinesPerController, done)
+			return runServer(healthServer, done)
 		},
 	)
 	if err != nil {
@@ -146,8 +290,67 @@ func main() {
 	}
 }
 
+// limitRoutines runs controllers with the maximum number of workers and limits how many of them reconcile at once
+// to the configured routines per controller, so that the number of routines can change without a restart
+func limitRoutines(configStore *config.Store, impl *controller.Impl, multiplier int) {
+	limited := reconciler.Limit(impl.Reconciler, func() int {
+		return multiplier * configStore.Load().RoutinesPerController
+	})
+	configStore.OnChange(func(*config.Config) {
+		limited.LimitChanged()
+	})
+	impl.Reconciler = limited
+}
+
+// labelLeader marks the pod of the leader so that the git webhook service only routes to it. Failing to
+// label the pod does not stop reconciling, git push webhooks fall back to polling.
+func labelLeader(logger *zap.SugaredLogger, labeler *leaderelection.LeaderLabeler, leader bool) {
+	if labeler.PodName == "" {
+		return
+	}
+
+	if err := labeler.Label(leader); err != nil {
+		logger.Errorw("Error labeling leader pod", zap.String("pod", labeler.PodName), zap.Error(err))
+	}
+}
+
+// identity names this replica in the leader lease
+func identity() string {
+	if podName := os.Getenv("POD_NAME"); podName != "" {
+		return podName
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "kpack-controller"
+	}
+	return hostname
+}
+
 type doneFunc func(done <-chan struct{}) error
 
+func untilClosed(ch <-chan struct{}) doneFunc {
+	return func(done <-chan struct{}) error {
+		select {
+		case <-ch:
+		case <-done:
+		}
+		return nil
+	}
+}
+
+func runServer(server *http.Server, done <-chan struct{}) error {
+	go func() {
+		<-done
+		server.Shutdown(context.Background())
+	}()
+
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
 func runGroup(fns ...doneFunc) error {
 	var wg sync.WaitGroup
 	wg.Add(len(fns))
@@ -1,7 +1,9 @@
 package main
 
 import (
-	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
@@ -9,69 +11,119 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/pivotal/kpack/pkg/archive"
+	"github.com/pivotal/kpack/pkg/blob"
 )
 
 func downloadBlob(dir string, logger *log.Logger) {
-	blob, err := url.Parse(*blobURL)
+	location, err := url.Parse(*blobURL)
 	if err != nil {
 		logger.Fatal(err.Error())
 	}
 
-	resp, err := http.Get(*blobURL)
+	headers, err := blobAuthHeaders(*blobURL, blobSecrets)
 	if err != nil {
-		logger.Fatal(err.Error())
+		logger.Fatalf("Failed to read blob secrets: %s", err)
 	}
-	defer resp.Body.Close()
 
-	file, err := ioutil.TempFile("", "")
-	if err != nil {
-		logger.Fatal(err.Error())
-	}
-	defer os.RemoveAll(file.Name())
-
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
-		logger.Fatal(err.Error())
+	if err := fetchBlob(*blobURL, *blobSHA256, headers, dir); err != nil {
+		logger.Fatalf("Failed to fetch blob %s: %s", location.Host+location.Path, err)
 	}
 
-	zipReader, err := zip.OpenReader(file.Name())
-	if err != nil {
-		logger.Fatal(err.Error())
-	}
-	defer zipReader.Close()
+	logger.Printf("Successfully downloaded %s in path %q", location.Host+location.Path, dir)
+}
 
-	for _, file := range zipReader.File {
-		filePath := filepath.Join(dir, file.Name)
-		if file.FileInfo().IsDir() {
-			err := os.MkdirAll(filePath, file.Mode())
-			if err != nil {
-				logger.Fatal(err.Error())
-			}
-			continue
+// blobAuthHeaders reads the first mounted blob secret annotated with a url matching the blob url
+func blobAuthHeaders(blobURL string, secrets []string) (http.Header, error) {
+	for _, secret := range secrets {
+		parts := strings.SplitN(secret, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid blob secret %s", secret)
 		}
+		secretPath, annotatedURL := parts[0], parts[1]
 
-		if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
-			logger.Fatal(err.Error())
+		if !blob.URLMatch(blobURL, annotatedURL) {
+			continue
 		}
 
-		outFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
+		files, err := ioutil.ReadDir(secretPath)
 		if err != nil {
-			logger.Fatal(err.Error())
+			return nil, err
 		}
 
-		srcFile, err := file.Open()
-		if err != nil {
-			logger.Fatal(err.Error())
+		data := map[string][]byte{}
+		for _, file := range files {
+			// skip the timestamped directories and links kubernetes uses to update secret volumes
+			if strings.HasPrefix(file.Name(), "..") {
+				continue
+			}
+
+			value, err := ioutil.ReadFile(filepath.Join(secretPath, file.Name()))
+			if err != nil {
+				return nil, err
+			}
+			data[file.Name()] = value
 		}
+		return blob.AuthHeaders(data), nil
+	}
+	return http.Header{}, nil
+}
 
-		_, err = io.Copy(outFile, srcFile)
+func fetchBlob(blobURL, expectedSHA256 string, headers http.Header, dir string) error {
+	req, err := http.NewRequest(http.MethodGet, blobURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header = headers
 
-		outFile.Close()
-		srcFile.Close()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
-		if err != nil {
-			logger.Fatal(err.Error())
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	file, err := ioutil.TempFile("", "")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(file.Name())
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "unable to download blob")
+	}
+
+	if expectedSHA256 != "" {
+		actualSHA256 := hex.EncodeToString(hash.Sum(nil))
+		if !strings.EqualFold(actualSHA256, expectedSHA256) {
+			return fmt.Errorf("sha256 mismatch: expected %s but downloaded %s", expectedSHA256, actualSHA256)
 		}
 	}
-	logger.Printf("Successfully downloaded %s in path %q", blob.Host+blob.Path, dir)
+
+	header := make([]byte, 512)
+	n, err := file.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	format, err := archive.DetectFormat(resp.Header.Get("Content-Type"), resp.Request.URL.Path, header[:n])
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return archive.Extract(file, format, dir)
 }
@@ -6,15 +6,34 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"strings"
 )
 
 var (
 	gitURL        = flag.String("git-url", os.Getenv("GIT_URL"), "The url of the Git repository to initialize.")
 	gitRevision   = flag.String("git-revision", os.Getenv("GIT_REVISION"), "The Git revision to make the repository HEAD.")
 	blobURL       = flag.String("blob-url", os.Getenv("BLOB_URL"), "The url of the source code blob.")
+	blobSHA256    = flag.String("blob-sha256", os.Getenv("BLOB_SHA256"), "The expected sha256 checksum of the source code blob.")
 	registryImage = flag.String("registry-image", os.Getenv("REGISTRY_IMAGE"), "The registry location of the source code image.")
 )
 
+var blobSecrets stringSlice
+
+func init() {
+	flag.Var(&blobSecrets, "blob-secret", "A mounted blob secret in the form <path>=<url>. May be repeated.")
+}
+
+type stringSlice []string
+
+func (s *stringSlice) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSlice) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
 func run(logger *log.Logger, cmd string, args ...string) {
 	c := exec.Command(cmd, args...)
 	var output bytes.Buffer
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/knative/pkg/signals"
+	"github.com/knative/pkg/system"
+	"github.com/knative/pkg/webhook"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+)
+
+const (
+	serviceName = "kpack-webhook"
+	webhookName = "resource.webhook.kpack.pivotal.io"
+	secretName  = "kpack-webhook-certs"
+	webhookPort = 8443
+)
+
+var (
+	kubeconfig = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	masterURL  = flag.String("master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+)
+
+func main() {
+	flag.Parse()
+	devLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("Couldn't create logger: %s", err)
+	}
+	logger := devLogger.Sugar()
+
+	clusterConfig, err := clientcmd.BuildConfigFromFlags(*masterURL, *kubeconfig)
+	if err != nil {
+		logger.Fatalf("Error building kubeconfig: %v", err)
+	}
+
+	k8sClient, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		logger.Fatalf("could not get kubernetes client: %s", err.Error())
+	}
+
+	controller := webhook.AdmissionController{
+		Client: k8sClient,
+		Options: webhook.ControllerOptions{
+			ServiceName:    serviceName,
+			DeploymentName: serviceName,
+			Namespace:      system.Namespace(),
+			Port:           webhookPort,
+			SecretName:     secretName,
+			WebhookName:    webhookName,
+		},
+		Handlers: map[schema.GroupVersionKind]webhook.GenericCRD{
+			v1alpha1.SchemeGroupVersion.WithKind("Image"):                     &v1alpha1.Image{},
+			v1alpha1.SchemeGroupVersion.WithKind("Build"):                     &v1alpha1.Build{},
+			v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.BuilderKind):        &v1alpha1.Builder{},
+			v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.ClusterBuilderKind): &v1alpha1.ClusterBuilder{},
+			v1alpha1.SchemeGroupVersion.WithKind("SourceResolver"):            &v1alpha1.SourceResolver{},
+		},
+		Logger:                logger,
+		DisallowUnknownFields: true,
+	}
+
+	if err := controller.Run(signals.SetupSignalHandler()); err != nil {
+		logger.Fatalf("Error running admission controller: %v", err)
+	}
+}
@@ -1,12 +1,22 @@
 package v1alpha1
 
 import (
+	"math"
+	"time"
+
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/kmeta"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+const (
+	BuildCancelAnnotation = "build.pivotal.io/cancel"
+
+	BuildTimedOutReason = "BuildTimedOut"
+	BuildCanceledReason = "BuildCanceled"
+)
+
 func (bi *BuilderImage) getBuilderSecretVolume() corev1.Volume {
 	if len(bi.ImagePullSecrets) > 0 {
 		return corev1.Volume{
@@ -116,3 +126,33 @@ func (b *Build) BuildEnvVars() []corev1.EnvVar {
 func (b *Build) ImagePullSecretsVolume() corev1.Volume {
 	return b.Spec.Source.Source().ImagePullSecretsVolume()
 }
+
+func (b *Build) IsRebase() bool {
+	return b.Spec.LastBuild != nil
+}
+
+func (b *Build) CancelRequested() bool {
+	return b.Annotations[BuildCancelAnnotation] == "true"
+}
+
+func (b *Build) HasTimeout() bool {
+	return b.Spec.Timeout != nil
+}
+
+// Deadline is the time by which the build must have finished.
+func (b *Build) Deadline() time.Time {
+	return b.CreationTimestamp.Add(b.Spec.Timeout.Duration)
+}
+
+func (b *Build) TimedOut(now time.Time) bool {
+	return b.HasTimeout() && !now.Before(b.Deadline())
+}
+
+func (b *Build) activeDeadlineSeconds() *int64 {
+	if !b.HasTimeout() {
+		return nil
+	}
+
+	seconds := int64(math.Ceil(b.Spec.Timeout.Duration.Seconds()))
+	return &seconds
+}
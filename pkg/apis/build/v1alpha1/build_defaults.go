@@ -18,10 +18,14 @@ package v1alpha1
 
 import "context"
 
-func (a *Build) SetDefaults(ctx context.Context) {
-	a.Spec.SetDefaults(ctx)
+const defaultServiceAccount = "default"
+
+func (b *Build) SetDefaults(ctx context.Context) {
+	b.Spec.SetDefaults(ctx)
 }
 
-func (as *BuildSpec) SetDefaults(ctx context.Context) {
-	// nothing to do
+func (bs *BuildSpec) SetDefaults(ctx context.Context) {
+	if bs.ServiceAccount == "" {
+		bs.ServiceAccount = defaultServiceAccount
+	}
 }
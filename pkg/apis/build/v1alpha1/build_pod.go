@@ -3,6 +3,8 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/knative/pkg/kmeta"
 	corev1 "k8s.io/api/core/v1"
@@ -16,6 +18,13 @@ const (
 	BuildLabel                   = "build.pivotal.io/build"
 	DOCKERSecretAnnotationPrefix = "build.pivotal.io/docker"
 	GITSecretAnnotationPrefix    = "build.pivotal.io/git"
+	BlobSecretAnnotationPrefix   = "build.pivotal.io/blob"
+	BlobSecretTemplateName       = "blob-secret-volume-%s"
+	BlobSecretPathName           = "/var/blob-secrets/%s"
+	CompletionContainerName      = "completion"
+
+	// rebaserPreviousImageLifecycleVersion is the first lifecycle whose rebaser accepts -previous-image
+	rebaserPreviousImageLifecycleVersion = "0.6.0"
 
 	cacheDirName              = "cache-dir"
 	layersDirName             = "layers-dir"
@@ -24,13 +33,20 @@ const (
 	workspaceDir              = "workspace-dir"
 	imagePullSecretsDirName   = "image-pull-secrets-dir"
 	builderPullSecretsDirName = "builder-pull-secrets-dir"
+
+	platformEnvVarPrefix = "PLATFORM_ENV_"
+
+	bindingMetadataVolumeTemplateName = "binding-metadata-%d"
+	bindingSecretVolumeTemplateName   = "binding-secret-%d"
+	bindingMetadataPathTemplate       = "/platform/bindings/%s/metadata"
+	bindingSecretPathTemplate         = "/platform/bindings/%s/secret"
 )
 
 type BuildPodConfig struct {
 	SourceInitImage string
 	BuildInitImage  string
 	CredsInitImage  string
-	NopImage        string
+	CompletionImage string
 }
 
 var (
@@ -71,6 +87,11 @@ var (
 )
 
 func (b *Build) BuildPod(config BuildPodConfig, secrets []corev1.Secret, builder BuilderImage) (*corev1.Pod, error) {
+	// Older lifecycles can only rebase the image a tag currently points to, run the full build instead
+	if b.IsRebase() && builder.supportsRebaseByDigest() {
+		return b.rebasePod(config, secrets, builder)
+	}
+
 	var root int64 = 0
 
 	buf, err := json.Marshal(b.Spec.Env)
@@ -86,6 +107,12 @@ func (b *Build) BuildPod(config BuildPodConfig, secrets []corev1.Secret, builder
 	}
 	volumes = append(volumes, secretVolumes...)
 
+	bindingVolumes, bindingVolumeMounts := b.setupBindings()
+	volumes = append(volumes, bindingVolumes...)
+
+	blobSecretVolumes, blobSecretVolumeMounts, blobSecretArgs := b.setupBlobSecretVolumesAndArgs(secrets)
+	volumes = append(volumes, blobSecretVolumes...)
+
 	builderImage := builder.Image
 
 	workspaceVolume := corev1.VolumeMount{
@@ -110,19 +137,30 @@ func (b *Build) BuildPod(config BuildPodConfig, secrets []corev1.Secret, builder
 			RestartPolicy: corev1.RestartPolicyNever,
 			Containers: []corev1.Container{
 				{
-					Name:            "nop",
-					Image:           config.NopImage,
+					Name:                     CompletionContainerName,
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    config.CompletionImage,
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser:  &root,
+						RunAsGroup: &root,
+					},
+					Env: completionEnv(b, builder),
+					VolumeMounts: []corev1.VolumeMount{
+						homeVolume,
+						layersVolume,
+					},
 					ImagePullPolicy: corev1.PullIfNotPresent,
 					Resources:       b.Spec.Resources,
 				},
 			},
 			InitContainers: []corev1.Container{
 				{
-					Name:            "creds-init",
-					Image:           config.CredsInitImage,
-					Args:            secretArgs,
-					ImagePullPolicy: corev1.PullIfNotPresent,
-					VolumeMounts:    append(secretVolumeMounts, homeVolume),
+					Name:                     "creds-init",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    config.CredsInitImage,
+					Args:                     secretArgs,
+					ImagePullPolicy:          corev1.PullIfNotPresent,
+					VolumeMounts:             append(secretVolumeMounts, homeVolume),
 					Env: []corev1.EnvVar{
 						{
 							Name:  "HOME",
@@ -131,29 +169,32 @@ func (b *Build) BuildPod(config BuildPodConfig, secrets []corev1.Secret, builder
 					},
 				},
 				{
-					Name:  "source-init",
-					Image: config.SourceInitImage,
+					Name:                     "source-init",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    config.SourceInitImage,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser:  &root,
 						RunAsGroup: &root,
 					},
 					Env:             b.BuildEnvVars(),
+					Args:            blobSecretArgs,
 					ImagePullPolicy: corev1.PullIfNotPresent,
 					WorkingDir:      "/workspace",
-					VolumeMounts: []corev1.VolumeMount{
+					VolumeMounts: append([]corev1.VolumeMount{
 						imagePullSecretsVolume,
 						sourceVolume,
 						homeVolume,
-					},
+					}, blobSecretVolumeMounts...),
 				},
 				{
-					Name:  "prepare",
-					Image: config.BuildInitImage,
+					Name:                     "prepare",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    config.BuildInitImage,
 					SecurityContext: &corev1.SecurityContext{
 						RunAsUser:  &root,
 						RunAsGroup: &root,
 					},
-					Env: []corev1.EnvVar{
+					Env: append([]corev1.EnvVar{
 						{
 							Name:  "BUILDER",
 							Value: builderImage,
@@ -167,7 +208,7 @@ func (b *Build) BuildPod(config BuildPodConfig, secrets []corev1.Secret, builder
 							Value: b.Tag(),
 						},
 						homeEnv,
-					},
+					}, b.platformEnvVarRefs()...),
 					VolumeMounts: []corev1.VolumeMount{
 						{
 							Name:      layersDirName,
@@ -182,40 +223,37 @@ func (b *Build) BuildPod(config BuildPodConfig, secrets []corev1.Secret, builder
 					ImagePullPolicy: corev1.PullIfNotPresent,
 				},
 				{
-					Name:    "detect",
-					Image:   builderImage,
-					Command: []string{"/lifecycle/detector"},
+					Name:                     "detect",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    builderImage,
+					Command:                  []string{"/lifecycle/detector"},
 					Args: []string{
 						"-app=/workspace",
 						"-group=/layers/group.toml",
 						"-plan=/layers/plan.toml",
 					},
-					VolumeMounts: []corev1.VolumeMount{
+					VolumeMounts: append([]corev1.VolumeMount{
 						layersVolume,
 						platformVolume,
 						workspaceVolume,
-					},
+					}, bindingVolumeMounts...),
 					ImagePullPolicy: corev1.PullIfNotPresent,
 				},
 				{
-					Name:    "restore",
-					Image:   builderImage,
-					Command: []string{"/lifecycle/restorer"},
-					Args: []string{
-						"-group=/layers/group.toml",
-						"-layers=/layers",
-						"-path=/cache",
-					},
-					VolumeMounts: []corev1.VolumeMount{
-						layersVolume,
-						cacheVolume,
-					},
-					ImagePullPolicy: corev1.PullIfNotPresent,
+					Name:                     "restore",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    builderImage,
+					Command:                  []string{"/lifecycle/restorer"},
+					Args:                     b.cacheArgs(),
+					VolumeMounts:             b.cacheVolumeMounts(),
+					Env:                      b.cacheEnv(),
+					ImagePullPolicy:          corev1.PullIfNotPresent,
 				},
 				{
-					Name:    "analyze",
-					Image:   builderImage,
-					Command: []string{"/lifecycle/analyzer"},
+					Name:                     "analyze",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    builderImage,
+					Command:                  []string{"/lifecycle/analyzer"},
 					Args: []string{
 						"-layers=/layers",
 						"-helpers=false",
@@ -234,27 +272,29 @@ func (b *Build) BuildPod(config BuildPodConfig, secrets []corev1.Secret, builder
 					ImagePullPolicy: corev1.PullIfNotPresent,
 				},
 				{
-					Name:    "build",
-					Image:   builderImage,
-					Command: []string{"/lifecycle/builder"},
+					Name:                     "build",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    builderImage,
+					Command:                  []string{"/lifecycle/builder"},
 					Args: []string{
 						"-layers=/layers",
 						"-app=/workspace",
 						"-group=/layers/group.toml",
 						"-plan=/layers/plan.toml",
 					},
-					VolumeMounts: []corev1.VolumeMount{
+					VolumeMounts: append([]corev1.VolumeMount{
 						layersVolume,
 						platformVolume,
 						workspaceVolume,
-					},
+					}, bindingVolumeMounts...),
 					ImagePullPolicy: corev1.PullIfNotPresent,
 				},
 				{
-					Name:    "export",
-					Image:   builderImage,
-					Command: []string{"/lifecycle/exporter"},
-					Args:    buildExporterArgs(b),
+					Name:                     "export",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    builderImage,
+					Command:                  []string{"/lifecycle/exporter"},
+					Args:                     buildExporterArgs(b, builder),
 					VolumeMounts: []corev1.VolumeMount{
 						layersVolume,
 						workspaceVolume,
@@ -266,36 +306,251 @@ func (b *Build) BuildPod(config BuildPodConfig, secrets []corev1.Secret, builder
 					ImagePullPolicy: corev1.PullIfNotPresent,
 				},
 				{
-					Name:    "cache",
-					Image:   builderImage,
-					Command: []string{"/lifecycle/cacher"},
-					Args: []string{
-						"-group=/layers/group.toml",
-						"-layers=/layers",
-						"-path=/cache",
+					Name:                     "cache",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    builderImage,
+					Command:                  []string{"/lifecycle/cacher"},
+					Args:                     b.cacheArgs(),
+					VolumeMounts:             b.cacheVolumeMounts(),
+					Env:                      b.cacheEnv(),
+					ImagePullPolicy:          corev1.PullIfNotPresent,
+				},
+			},
+			ServiceAccountName:    b.Spec.ServiceAccount,
+			Volumes:               volumes,
+			ImagePullSecrets:      builder.ImagePullSecrets,
+			ActiveDeadlineSeconds: b.activeDeadlineSeconds(),
+			NodeSelector:          b.Spec.NodeSelector,
+			Tolerations:           b.Spec.Tolerations,
+			Affinity:              b.Spec.Affinity,
+			PriorityClassName:     b.Spec.PriorityClassName,
+			RuntimeClassName:      b.Spec.RuntimeClassName,
+		},
+	}, nil
+}
+
+// rebasePod creates a pod that only swaps the run image layers of the last built image
+// with the builder's current run image, skipping the rest of the build.
+func (b *Build) rebasePod(config BuildPodConfig, secrets []corev1.Secret, builder BuilderImage) (*corev1.Pod, error) {
+	var root int64 = 0
+
+	volumes := append(b.setupVolumes(), builder.getBuilderSecretVolume())
+	secretVolumes, secretVolumeMounts, secretArgs, err := b.setupSecretVolumesAndArgs(secrets)
+	if err != nil {
+		return nil, err
+	}
+	volumes = append(volumes, secretVolumes...)
+
+	return &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      b.PodName(),
+			Namespace: b.Namespace(),
+			Labels: b.labels(map[string]string{
+				BuildLabel: b.Name,
+			}),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(b),
+			},
+		},
+		Spec: corev1.PodSpec{
+			// If the build fails, don't restart it.
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:                     CompletionContainerName,
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    config.CompletionImage,
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser:  &root,
+						RunAsGroup: &root,
 					},
+					Env: completionEnv(b, builder),
 					VolumeMounts: []corev1.VolumeMount{
-						layersVolume,
-						cacheVolume,
+						homeVolume,
 					},
 					ImagePullPolicy: corev1.PullIfNotPresent,
+					Resources:       b.Spec.Resources,
 				},
 			},
-			ServiceAccountName: b.Spec.ServiceAccount,
-			Volumes:            volumes,
-			ImagePullSecrets:   builder.ImagePullSecrets,
+			InitContainers: []corev1.Container{
+				{
+					Name:                     "creds-init",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    config.CredsInitImage,
+					Args:                     secretArgs,
+					ImagePullPolicy:          corev1.PullIfNotPresent,
+					VolumeMounts:             append(secretVolumeMounts, homeVolume),
+					Env: []corev1.EnvVar{
+						homeEnv,
+					},
+				},
+				{
+					Name:                     "rebase",
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					Image:                    builder.Image,
+					Command:                  []string{"/lifecycle/rebaser"},
+					Args:                     buildRebaserArgs(b, builder),
+					VolumeMounts: []corev1.VolumeMount{
+						homeVolume,
+					},
+					Env: []corev1.EnvVar{
+						homeEnv,
+					},
+					ImagePullPolicy: corev1.PullIfNotPresent,
+				},
+			},
+			ServiceAccountName:    b.Spec.ServiceAccount,
+			Volumes:               volumes,
+			ImagePullSecrets:      builder.ImagePullSecrets,
+			ActiveDeadlineSeconds: b.activeDeadlineSeconds(),
+			NodeSelector:          b.Spec.NodeSelector,
+			Tolerations:           b.Spec.Tolerations,
+			Affinity:              b.Spec.Affinity,
+			PriorityClassName:     b.Spec.PriorityClassName,
+			RuntimeClassName:      b.Spec.RuntimeClassName,
 		},
 	}, nil
 }
 
-func buildExporterArgs(build *Build) []string {
+// buildRebaserArgs rebases the last built image by digest, rather than whatever the tag currently
+// points to, and writes the result to all of the build tags.
+func buildRebaserArgs(build *Build, builder BuilderImage) []string {
 	return append([]string{
+		"-run-image=" + builder.RunImage,
+		"-previous-image=" + build.Spec.LastBuild.Image,
+		"-helpers=false",
+	}, build.Spec.Tags...)
+}
+
+// supportsRebaseByDigest reports whether the rebaser of the builder's lifecycle accepts -previous-image.
+// Builders that do not publish their lifecycle version are treated as older lifecycles.
+func (bi BuilderImage) supportsRebaseByDigest() bool {
+	version, ok := parseVersion(bi.LifecycleVersion)
+	if !ok {
+		return false
+	}
+
+	minimum, _ := parseVersion(rebaserPreviousImageLifecycleVersion)
+	for i := range version {
+		if version[i] != minimum[i] {
+			return version[i] > minimum[i]
+		}
+	}
+	return true
+}
+
+// parseVersion parses a major.minor.patch version, ignoring a leading v and any pre-release or build suffix
+func parseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) != len(parsed) {
+		return parsed, false
+	}
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
+
+// platformEnvVarRefs exposes build env vars that reference secrets or config maps to the
+// prepare step, which writes their resolved values to the platform dir.
+func (b *Build) platformEnvVarRefs() []corev1.EnvVar {
+	var refs []corev1.EnvVar
+	for _, envVar := range b.Spec.Env {
+		if envVar.ValueFrom == nil {
+			continue
+		}
+
+		refs = append(refs, corev1.EnvVar{
+			Name:      platformEnvVarPrefix + envVar.Name,
+			ValueFrom: envVar.ValueFrom,
+		})
+	}
+	return refs
+}
+
+// buildExporterArgs pins the exporter to the builder's run image so that the completion step
+// can report the run image without reading it back from the registry.
+func buildExporterArgs(build *Build, builder BuilderImage) []string {
+	args := []string{
 		"-layers=/layers",
 		"-helpers=false",
 		"-app=/workspace",
 		"-group=/layers/group.toml",
 		"-analyzed=/layers/analyzed.toml",
-	}, build.Spec.Tags...)
+	}
+	if builder.RunImage != "" {
+		args = append(args, "-run-image="+builder.RunImage)
+	}
+	return append(args, build.Spec.Tags...)
+}
+
+// completionEnv passes the completion step what it reports about the built image besides the
+// buildpacks, which it reads from the group.toml in the layers dir, and the image digest.
+func completionEnv(build *Build, builder BuilderImage) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  "IMAGE_TAG",
+			Value: build.Tag(),
+		},
+		{
+			Name:  "RUN_IMAGE",
+			Value: builder.RunImage,
+		},
+		{
+			Name:  "STACK_ID",
+			Value: builder.StackID,
+		},
+		homeEnv,
+	}
+}
+
+// cacheArgs configures the restore and cache steps to use the cache image in the registry
+// when one is set, otherwise the cache volume.
+func (b *Build) cacheArgs() []string {
+	cache := "-path=/cache"
+	if b.Spec.CacheImage != "" {
+		cache = "-image=" + b.Spec.CacheImage
+	}
+
+	return []string{
+		"-group=/layers/group.toml",
+		"-layers=/layers",
+		cache,
+	}
+}
+
+func (b *Build) cacheVolumeMounts() []corev1.VolumeMount {
+	if b.Spec.CacheImage != "" {
+		return []corev1.VolumeMount{
+			layersVolume,
+			homeVolume,
+		}
+	}
+
+	return []corev1.VolumeMount{
+		layersVolume,
+		cacheVolume,
+	}
+}
+
+// cacheEnv provides the registry credentials written by creds-init to steps using the cache image.
+func (b *Build) cacheEnv() []corev1.EnvVar {
+	if b.Spec.CacheImage != "" {
+		return []corev1.EnvVar{homeEnv}
+	}
+	return nil
 }
 
 func (b *Build) cacheVolume() corev1.VolumeSource {
@@ -347,12 +602,99 @@ func (b *Build) setupSecretVolumesAndArgs(secrets []corev1.Secret) ([]corev1.Vol
 			secretType = "git"
 		}
 
-		args = append(args, fmt.Sprintf("-basic-%s=%s=%s", secretType, secret.Name, annotatedUrl))
+		authType := "basic"
+		if secret.Type == corev1.SecretTypeSSHAuth {
+			authType = "ssh"
+		}
+
+		args = append(args, fmt.Sprintf("-%s-%s=%s=%s", authType, secretType, secret.Name, annotatedUrl))
 	}
 
 	return volumes, volumeMounts, args, nil
 }
 
+// setupBlobSecretVolumesAndArgs mounts the blob secrets into source-init, which picks the secret matching the blob url.
+func (b *Build) setupBlobSecretVolumesAndArgs(secrets []corev1.Secret) ([]corev1.Volume, []corev1.VolumeMount, []string) {
+	var (
+		volumes      []corev1.Volume
+		volumeMounts []corev1.VolumeMount
+		args         []string
+	)
+	if b.Spec.Source.Blob == nil {
+		return nil, nil, nil
+	}
+
+	for _, secret := range secrets {
+		annotatedUrl := secret.Annotations[BlobSecretAnnotationPrefix]
+		if annotatedUrl == "" {
+			continue
+		}
+		volumeName := fmt.Sprintf(BlobSecretTemplateName, secret.Name)
+		secretPath := fmt.Sprintf(BlobSecretPathName, secret.Name)
+
+		volumes = append(volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secret.Name,
+				},
+			},
+		})
+
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: secretPath,
+			ReadOnly:  true,
+		})
+
+		args = append(args, fmt.Sprintf("-blob-secret=%s=%s", secretPath, annotatedUrl))
+	}
+
+	return volumes, volumeMounts, args
+}
+
+func (b *Build) setupBindings() ([]corev1.Volume, []corev1.VolumeMount) {
+	var (
+		volumes      []corev1.Volume
+		volumeMounts []corev1.VolumeMount
+	)
+	for i, binding := range b.Spec.Bindings {
+		if binding.MetadataRef != nil {
+			volumeName := fmt.Sprintf(bindingMetadataVolumeTemplateName, i)
+			volumes = append(volumes, corev1.Volume{
+				Name: volumeName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: *binding.MetadataRef,
+					},
+				},
+			})
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: fmt.Sprintf(bindingMetadataPathTemplate, binding.Name),
+				ReadOnly:  true,
+			})
+		}
+
+		volumeName := fmt.Sprintf(bindingSecretVolumeTemplateName, i)
+		volumes = append(volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: binding.SecretRef.Name,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: fmt.Sprintf(bindingSecretPathTemplate, binding.Name),
+			ReadOnly:  true,
+		})
+	}
+
+	return volumes, volumeMounts
+}
+
 func (b *Build) setupVolumes() []corev1.Volume {
 	volumes := []corev1.Volume{
 		{
@@ -3,6 +3,7 @@ package v1alpha1_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/knative/pkg/kmeta"
 	"github.com/sclevine/spec"
@@ -106,7 +107,7 @@ func testBuildPod(t *testing.T, when spec.G, it spec.S) {
 		SourceInitImage: "git/init:image",
 		BuildInitImage:  "build/init:image",
 		CredsInitImage:  "creds/init:image",
-		NopImage:        "no/op:image",
+		CompletionImage: "completion/image:image",
 	}
 
 	when("BuildPod", func() {
@@ -194,6 +195,27 @@ func testBuildPod(t *testing.T, when spec.G, it spec.S) {
 			}, pod.Spec.InitContainers[0].VolumeMounts)
 		})
 
+		it("configures creds init with ssh git secrets", func() {
+			secrets = append(secrets, corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "git-ssh-secret",
+					Annotations: map[string]string{
+						v1alpha1.GITSecretAnnotationPrefix: "github.com",
+					},
+				},
+				Type: corev1.SecretTypeSSHAuth,
+			})
+
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			assert.Contains(t, pod.Spec.InitContainers[0].Args, "-ssh-git=git-ssh-secret=github.com")
+			assert.Contains(t, pod.Spec.InitContainers[0].VolumeMounts, corev1.VolumeMount{
+				Name:      "secret-volume-git-ssh-secret",
+				MountPath: "/var/build-secrets/git-ssh-secret",
+			})
+		})
+
 		it("configures source init with the git source", func() {
 			pod, err := build.BuildPod(config, secrets, imageRef)
 			require.NoError(t, err)
@@ -242,6 +264,73 @@ func testBuildPod(t *testing.T, when spec.G, it spec.S) {
 			}, pod.Spec.InitContainers[1].Env)
 		})
 
+		it("configures source init with the blob checksum", func() {
+			build.Spec.Source.Git = nil
+			build.Spec.Source.Blob = &v1alpha1.Blob{
+				URL:    "https://some-blobstore.example.com/some-blob",
+				SHA256: "f4d1d3fbd0a8e6b8f0d6a1d3f3b1c1a0e2b7e8d5c4b3a2918070605040302010",
+			}
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			assert.Equal(t, []corev1.EnvVar{
+				{
+					Name:  "BLOB_URL",
+					Value: "https://some-blobstore.example.com/some-blob",
+				},
+				{
+					Name:  "BLOB_SHA256",
+					Value: "f4d1d3fbd0a8e6b8f0d6a1d3f3b1c1a0e2b7e8d5c4b3a2918070605040302010",
+				},
+				{
+					Name:  "HOME",
+					Value: "/builder/home",
+				},
+			}, pod.Spec.InitContainers[1].Env)
+		})
+
+		it("mounts blob secrets into source init", func() {
+			build.Spec.Source.Git = nil
+			build.Spec.Source.Blob = &v1alpha1.Blob{
+				URL: "https://some-blobstore.example.com/some-blob",
+			}
+			blobSecrets := append([]corev1.Secret{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "blob-secret",
+						Annotations: map[string]string{
+							v1alpha1.BlobSecretAnnotationPrefix: "some-blobstore.example.com",
+						},
+					},
+					Type: corev1.SecretTypeOpaque,
+				},
+			}, secrets...)
+
+			pod, err := build.BuildPod(config, blobSecrets, imageRef)
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{
+				"-blob-secret=/var/blob-secrets/blob-secret=some-blobstore.example.com",
+			}, pod.Spec.InitContainers[1].Args)
+			assert.Contains(t, pod.Spec.InitContainers[1].VolumeMounts, corev1.VolumeMount{
+				Name:      "blob-secret-volume-blob-secret",
+				MountPath: "/var/blob-secrets/blob-secret",
+				ReadOnly:  true,
+			})
+			assert.Contains(t, pod.Spec.Volumes, corev1.Volume{
+				Name: "blob-secret-volume-blob-secret",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "blob-secret",
+					},
+				},
+			})
+
+			for _, arg := range pod.Spec.InitContainers[0].Args {
+				assert.NotContains(t, arg, "blob-secret")
+			}
+		})
+
 		it("configures source init with the registry source and empty imagePullSecrets when not provided", func() {
 			build.Spec.Source.Git = nil
 			build.Spec.Source.Blob = nil
@@ -270,6 +359,22 @@ func testBuildPod(t *testing.T, when spec.G, it spec.S) {
 			}, pod.Spec.InitContainers[1].Env)
 		})
 
+		it("configures source init with the registry image pinned to the resolved digest", func() {
+			build.Spec.Source.Git = nil
+			build.Spec.Source.Blob = nil
+			build.Spec.Source.Registry = &v1alpha1.Registry{
+				Image:  "some-registry.io/some-image:latest",
+				Digest: "sha256:f4d1d3fbd0a8e6b8f0d6a1d3f3b1c1a0e2b7e8d5c4b3a2918070605040302010",
+			}
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			assert.Contains(t, pod.Spec.InitContainers[1].Env, corev1.EnvVar{
+				Name:  "REGISTRY_IMAGE",
+				Value: "some-registry.io/some-image@sha256:f4d1d3fbd0a8e6b8f0d6a1d3f3b1c1a0e2b7e8d5c4b3a2918070605040302010",
+			})
+		})
+
 		it("configures source init with the registry source and a secret volume when is imagePullSecrets provided", func() {
 			build.Spec.Source.Git = nil
 			build.Spec.Source.Blob = nil
@@ -336,6 +441,37 @@ func testBuildPod(t *testing.T, when spec.G, it spec.S) {
 			}))
 		})
 
+		it("exposes env vars sourced from secrets and config maps to the prepare step", func() {
+			build.Spec.Env = append(build.Spec.Env, corev1.EnvVar{
+				Name: "NPM_TOKEN",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "npm"},
+						Key:                  "token",
+					},
+				},
+			})
+
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			prepare := pod.Spec.InitContainers[2]
+			assert.Equal(t, "prepare", prepare.Name)
+			assert.Contains(t, prepare.Env, corev1.EnvVar{
+				Name: "PLATFORM_ENV_NPM_TOKEN",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "npm"},
+						Key:                  "token",
+					},
+				},
+			})
+			assert.Contains(t, prepare.Env, corev1.EnvVar{
+				Name:  "PLATFORM_ENV_VARS",
+				Value: `[{"name":"keyA","value":"valueA"},{"name":"keyB","value":"valueB"},{"name":"NPM_TOKEN","valueFrom":{"secretKeyRef":{"name":"npm","key":"token"}}}]`,
+			})
+		})
+
 		it("configures detect step", func() {
 			pod, err := build.BuildPod(config, secrets, imageRef)
 			require.NoError(t, err)
@@ -417,6 +553,16 @@ func testBuildPod(t *testing.T, when spec.G, it spec.S) {
 			}, pod.Spec.InitContainers[7].Args)
 		})
 
+		it("pins the export step to the builder run image", func() {
+			imageRef.RunImage = "some/run@sha256:run-digest"
+
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			assert.Equal(t, "export", pod.Spec.InitContainers[7].Name)
+			assert.Contains(t, pod.Spec.InitContainers[7].Args, "-run-image=some/run@sha256:run-digest")
+		})
+
 		it("configures cache step", func() {
 			pod, err := build.BuildPod(config, secrets, imageRef)
 			require.NoError(t, err)
@@ -429,6 +575,44 @@ func testBuildPod(t *testing.T, when spec.G, it spec.S) {
 			}))
 		})
 
+		it("configures the restore and cache steps with the cache volume", func() {
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			for _, container := range []corev1.Container{pod.Spec.InitContainers[4], pod.Spec.InitContainers[8]} {
+				assert.Equal(t, []string{
+					"-group=/layers/group.toml",
+					"-layers=/layers",
+					"-path=/cache",
+				}, container.Args, container.Name)
+				assert.Equal(t, "cache-dir", container.VolumeMounts[1].Name, container.Name)
+			}
+		})
+
+		it("configures the restore and cache steps with the cache image", func() {
+			cacheImageBuild := build.DeepCopy()
+			cacheImageBuild.Spec.CacheName = ""
+			cacheImageBuild.Spec.CacheImage = "someimage/name:cache"
+
+			pod, err := cacheImageBuild.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			for _, container := range []corev1.Container{pod.Spec.InitContainers[4], pod.Spec.InitContainers[8]} {
+				assert.Equal(t, []string{
+					"-group=/layers/group.toml",
+					"-layers=/layers",
+					"-image=someimage/name:cache",
+				}, container.Args, container.Name)
+				assert.Equal(t, []corev1.VolumeMount{
+					{Name: "layers-dir", MountPath: "/layers"},
+					{Name: "home-dir", MountPath: "/builder/home"},
+				}, container.VolumeMounts, container.Name)
+				assert.Equal(t, []corev1.EnvVar{
+					{Name: "HOME", Value: "/builder/home"},
+				}, container.Env, container.Name)
+			}
+		})
+
 		it("configures the builder image in all lifecycle steps", func() {
 			pod, err := build.BuildPod(config, secrets, imageRef)
 			require.NoError(t, err)
@@ -440,12 +624,47 @@ func testBuildPod(t *testing.T, when spec.G, it spec.S) {
 			}
 		})
 
-		it("configures the nop container with resources", func() {
+		it("falls back to logs for the termination message of failed steps", func() {
 			pod, err := build.BuildPod(config, secrets, imageRef)
 			require.NoError(t, err)
 
-			nopContainer := pod.Spec.Containers[0]
-			assert.Equal(t, resources, nopContainer.Resources)
+			for _, container := range pod.Spec.InitContainers {
+				assert.Equal(t, corev1.TerminationMessageFallbackToLogsOnError, container.TerminationMessagePolicy, container.Name)
+			}
+		})
+
+		it("configures the completion container with resources", func() {
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			completionContainer := pod.Spec.Containers[0]
+			assert.Equal(t, "completion", completionContainer.Name)
+			assert.Equal(t, config.CompletionImage, completionContainer.Image)
+			assert.Equal(t, resources, completionContainer.Resources)
+		})
+
+		it("configures the completion container to report the built image", func() {
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			completionContainer := pod.Spec.Containers[0]
+			assert.Contains(t, completionContainer.Env, corev1.EnvVar{Name: "IMAGE_TAG", Value: build.Tag()})
+			assert.Contains(t, completionContainer.Env, corev1.EnvVar{Name: "HOME", Value: "/builder/home"})
+			assert.Equal(t, corev1.TerminationMessageFallbackToLogsOnError, completionContainer.TerminationMessagePolicy)
+			assert.Equal(t, "home-dir", completionContainer.VolumeMounts[0].Name)
+		})
+
+		it("gives the completion container the lifecycle outputs and the stack the image is built on", func() {
+			imageRef.RunImage = "some/run@sha256:run-digest"
+			imageRef.StackID = "io.buildpacks.stacks.bionic"
+
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			completionContainer := pod.Spec.Containers[0]
+			assert.Contains(t, completionContainer.Env, corev1.EnvVar{Name: "RUN_IMAGE", Value: "some/run@sha256:run-digest"})
+			assert.Contains(t, completionContainer.Env, corev1.EnvVar{Name: "STACK_ID", Value: "io.buildpacks.stacks.bionic"})
+			assert.Contains(t, completionContainer.VolumeMounts, corev1.VolumeMount{Name: "layers-dir", MountPath: "/layers"})
 		})
 
 		it("creates a pod with reusable cache when name is provided", func() {
@@ -484,6 +703,22 @@ func testBuildPod(t *testing.T, when spec.G, it spec.S) {
 			assertSecretNotPresent(t, pod, "random-secret-1")
 		})
 
+		it("does not set an active deadline when no timeout is provided", func() {
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			assert.Nil(t, pod.Spec.ActiveDeadlineSeconds)
+		})
+
+		it("sets the active deadline from the build timeout", func() {
+			build.Spec.Timeout = &metav1.Duration{Duration: 90 * time.Minute}
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			require.NotNil(t, pod.Spec.ActiveDeadlineSeconds)
+			assert.Equal(t, int64(5400), *pod.Spec.ActiveDeadlineSeconds)
+		})
+
 		it("attach image pull secrets to pod", func() {
 			pod, err := build.BuildPod(config, secrets, imageRef)
 			require.NoError(t, err)
@@ -491,6 +726,203 @@ func testBuildPod(t *testing.T, when spec.G, it spec.S) {
 			require.Len(t, pod.Spec.ImagePullSecrets, 1)
 			assert.Equal(t, corev1.LocalObjectReference{Name: "some-image-secret"}, pod.Spec.ImagePullSecrets[0])
 		})
+
+		it("mounts bindings into the detect and build steps", func() {
+			build.Spec.Bindings = []v1alpha1.Binding{
+				{
+					Name:        "apm",
+					MetadataRef: &corev1.LocalObjectReference{Name: "apm-metadata"},
+					SecretRef:   corev1.LocalObjectReference{Name: "apm-secret"},
+				},
+				{
+					Name:      "maven",
+					SecretRef: corev1.LocalObjectReference{Name: "maven-settings"},
+				},
+			}
+
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			assert.Contains(t, pod.Spec.Volumes, corev1.Volume{
+				Name: "binding-metadata-0",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "apm-metadata"},
+					},
+				},
+			})
+			assert.Contains(t, pod.Spec.Volumes, corev1.Volume{
+				Name: "binding-secret-0",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: "apm-secret"},
+				},
+			})
+			assert.Contains(t, pod.Spec.Volumes, corev1.Volume{
+				Name: "binding-secret-1",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: "maven-settings"},
+				},
+			})
+
+			for _, step := range []string{"detect", "build"} {
+				assert.Equal(t, corev1.VolumeMount{
+					Name:      "binding-metadata-0",
+					MountPath: "/platform/bindings/apm/metadata",
+					ReadOnly:  true,
+				}, getVolumeMountFromContainer(t, pod.Spec.InitContainers, step, "binding-metadata-0"))
+				assert.Equal(t, corev1.VolumeMount{
+					Name:      "binding-secret-0",
+					MountPath: "/platform/bindings/apm/secret",
+					ReadOnly:  true,
+				}, getVolumeMountFromContainer(t, pod.Spec.InitContainers, step, "binding-secret-0"))
+				assert.Equal(t, corev1.VolumeMount{
+					Name:      "binding-secret-1",
+					MountPath: "/platform/bindings/maven/secret",
+					ReadOnly:  true,
+				}, getVolumeMountFromContainer(t, pod.Spec.InitContainers, step, "binding-secret-1"))
+			}
+		})
+
+		it("configures the pod scheduling", func() {
+			runtimeClassName := "gvisor"
+			build.Spec.NodeSelector = map[string]string{"pool": "builds"}
+			build.Spec.Tolerations = []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "builds", Effect: corev1.TaintEffectNoSchedule},
+			}
+			build.Spec.Affinity = &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+						{
+							Weight: 100,
+							PodAffinityTerm: corev1.PodAffinityTerm{
+								TopologyKey: "kubernetes.io/hostname",
+							},
+						},
+					},
+				},
+			}
+			build.Spec.PriorityClassName = "builds"
+			build.Spec.RuntimeClassName = &runtimeClassName
+
+			pod, err := build.BuildPod(config, secrets, imageRef)
+			require.NoError(t, err)
+
+			assert.Equal(t, build.Spec.NodeSelector, pod.Spec.NodeSelector)
+			assert.Equal(t, build.Spec.Tolerations, pod.Spec.Tolerations)
+			assert.Equal(t, build.Spec.Affinity, pod.Spec.Affinity)
+			assert.Equal(t, "builds", pod.Spec.PriorityClassName)
+			assert.Equal(t, &runtimeClassName, pod.Spec.RuntimeClassName)
+		})
+
+		when("the build is a rebase of the last build", func() {
+			const runImage = "some/run@sha256:new-run-digest"
+
+			it.Before(func() {
+				imageRef.RunImage = runImage
+				imageRef.LifecycleVersion = "0.6.0"
+				build.Spec.LastBuild = &v1alpha1.LastBuild{
+					Image:   "someimage/name@sha256:last-built-digest",
+					StackID: "io.buildpacks.stacks.bionic",
+				}
+			})
+
+			it("only creates the creds init and rebase steps", func() {
+				pod, err := build.BuildPod(config, secrets, imageRef)
+				require.NoError(t, err)
+
+				var names []string
+				for _, container := range pod.Spec.InitContainers {
+					names = append(names, container.Name)
+				}
+				assert.Equal(t, []string{"creds-init", "rebase"}, names)
+			})
+
+			it("configures the rebase step with the new run image", func() {
+				pod, err := build.BuildPod(config, secrets, imageRef)
+				require.NoError(t, err)
+
+				rebase := pod.Spec.InitContainers[1]
+				assert.Equal(t, builderImage, rebase.Image)
+				assert.Equal(t, []string{"/lifecycle/rebaser"}, rebase.Command)
+				assert.Equal(t, []string{
+					"-run-image=some/run@sha256:new-run-digest",
+					"-previous-image=someimage/name@sha256:last-built-digest",
+					"-helpers=false",
+					"someimage/name", "someimage/name:tag2", "someimage/name:tag3",
+				}, rebase.Args)
+				assert.Contains(t, rebase.Env, corev1.EnvVar{Name: "HOME", Value: "/builder/home"})
+				assert.Equal(t, "home-dir", rebase.VolumeMounts[0].Name)
+			})
+
+			it("rebases the last built image by digest even if the tag has moved", func() {
+				build.Spec.LastBuild.Image = "someimage/name@sha256:other-digest"
+
+				pod, err := build.BuildPod(config, secrets, imageRef)
+				require.NoError(t, err)
+
+				rebase := pod.Spec.InitContainers[1]
+				assert.Contains(t, rebase.Args, "-previous-image=someimage/name@sha256:other-digest")
+				assert.NotContains(t, rebase.Args, "-previous-image=someimage/name")
+			})
+
+			it("runs the full build when the builder lifecycle cannot rebase by digest", func() {
+				for _, lifecycleVersion := range []string{"0.4.0", "0.5.1", ""} {
+					imageRef.LifecycleVersion = lifecycleVersion
+
+					pod, err := build.BuildPod(config, secrets, imageRef)
+					require.NoError(t, err)
+
+					var names []string
+					for _, container := range pod.Spec.InitContainers {
+						names = append(names, container.Name)
+					}
+					assert.NotContains(t, names, "rebase", "lifecycle %q", lifecycleVersion)
+					assert.Contains(t, names, "export", "lifecycle %q", lifecycleVersion)
+				}
+			})
+
+			it("rebases by digest with newer lifecycles", func() {
+				imageRef.LifecycleVersion = "v0.10.2"
+
+				pod, err := build.BuildPod(config, secrets, imageRef)
+				require.NoError(t, err)
+
+				assert.Equal(t, "rebase", pod.Spec.InitContainers[1].Name)
+			})
+
+			it("reports the rebased image from the completion container", func() {
+				pod, err := build.BuildPod(config, secrets, imageRef)
+				require.NoError(t, err)
+
+				require.Len(t, pod.Spec.Containers, 1)
+				completionContainer := pod.Spec.Containers[0]
+				assert.Equal(t, "completion", completionContainer.Name)
+				assert.Contains(t, completionContainer.Env, corev1.EnvVar{Name: "IMAGE_TAG", Value: build.Tag()})
+				assert.Equal(t, resources, completionContainer.Resources)
+			})
+
+			it("configures the pod scheduling", func() {
+				build.Spec.NodeSelector = map[string]string{"pool": "builds"}
+				build.Spec.PriorityClassName = "builds"
+
+				pod, err := build.BuildPod(config, secrets, imageRef)
+				require.NoError(t, err)
+
+				assert.Equal(t, build.Spec.NodeSelector, pod.Spec.NodeSelector)
+				assert.Equal(t, "builds", pod.Spec.PriorityClassName)
+			})
+
+			it("attaches secrets and the active deadline", func() {
+				build.Spec.Timeout = &metav1.Duration{Duration: 10 * time.Minute}
+				pod, err := build.BuildPod(config, secrets, imageRef)
+				require.NoError(t, err)
+
+				assertSecretPresent(t, pod, "docker-secret-1")
+				assert.Equal(t, serviceAccount, pod.Spec.ServiceAccountName)
+				require.NotNil(t, pod.Spec.ActiveDeadlineSeconds)
+				assert.Equal(t, int64(600), *pod.Spec.ActiveDeadlineSeconds)
+			})
+		})
 	})
 }
 
@@ -44,16 +44,42 @@ var (
 type BuilderImage struct {
 	Image            string                        `json:"image"`
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,15,rep,name=imagePullSecrets"`
+	RunImage         string                        `json:"runImage,omitempty"`
+	StackID          string                        `json:"stackId,omitempty"`
+	LifecycleVersion string                        `json:"lifecycleVersion,omitempty"`
 }
 
 type BuildSpec struct {
-	Tags           []string                    `json:"tags"`
-	Builder        BuilderImage                `json:"builder"`
-	ServiceAccount string                      `json:"serviceAccount"`
-	Source         SourceConfig                `json:"source"`
-	CacheName      string                      `json:"cacheName"`
-	Env            []corev1.EnvVar             `json:"env"`
-	Resources      corev1.ResourceRequirements `json:"resources"`
+	Tags              []string                    `json:"tags"`
+	Builder           BuilderImage                `json:"builder"`
+	ServiceAccount    string                      `json:"serviceAccount"`
+	Source            SourceConfig                `json:"source"`
+	CacheName         string                      `json:"cacheName"`
+	CacheImage        string                      `json:"cacheImage,omitempty"`
+	Env               []corev1.EnvVar             `json:"env"`
+	Resources         corev1.ResourceRequirements `json:"resources"`
+	Timeout           *metav1.Duration            `json:"timeout,omitempty"`
+	NodeSelector      map[string]string           `json:"nodeSelector,omitempty"`
+	Tolerations       []corev1.Toleration         `json:"tolerations,omitempty"`
+	Affinity          *corev1.Affinity            `json:"affinity,omitempty"`
+	PriorityClassName string                      `json:"priorityClassName,omitempty"`
+	RuntimeClassName  *string                     `json:"runtimeClassName,omitempty"`
+	Bindings          []Binding                   `json:"bindings,omitempty"`
+	LastBuild         *LastBuild                  `json:"lastBuild,omitempty"`
+}
+
+// Binding is mounted into the detect and build steps at /platform/bindings/<name>
+// following the Cloud Native Buildpacks binding layout.
+type Binding struct {
+	Name        string                       `json:"name"`
+	MetadataRef *corev1.LocalObjectReference `json:"metadataRef,omitempty"`
+	SecretRef   corev1.LocalObjectReference  `json:"secretRef"`
+}
+
+// LastBuild identifies the previously built image a rebase build is applied to.
+type LastBuild struct {
+	Image   string `json:"image"`
+	StackID string `json:"stackId,omitempty"`
 }
 
 type BuildStatus struct {
@@ -63,6 +89,12 @@ type BuildStatus struct {
 	PodName             string                  `json:"podName"`
 	StepStates          []corev1.ContainerState `json:"stepStates,omitempty"`
 	StepsCompleted      []string                `json:"stepsCompleted,omitempty"`
+	Stack               BuildStack              `json:"stack,omitempty"`
+}
+
+type BuildStack struct {
+	RunImage string `json:"runImage,omitempty"`
+	ID       string `json:"id,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -18,14 +18,33 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/knative/pkg/apis"
 )
 
-func (a *Build) Validate(ctx context.Context) *apis.FieldError {
-	return nil
+func (b *Build) Validate(ctx context.Context) *apis.FieldError {
+	return b.Spec.Validate(ctx).ViaField("spec")
 }
 
-func (as *BuildSpec) Validate(ctx context.Context) *apis.FieldError {
-	return nil
+func (bs *BuildSpec) Validate(ctx context.Context) *apis.FieldError {
+	return validateTags(bs.Tags).
+		Also(validateBuilderImage(bs.Builder.Image).ViaField("builder")).
+		Also(validateRequired(bs.ServiceAccount, "serviceAccount")).
+		Also(bs.Source.Validate(withinResolvedSource(ctx)).ViaField("source")).
+		Also(validateEnv(bs.Env).ViaField("env")).
+		Also(validateTimeout(bs.Timeout)).
+		Also(validateBindings(bs.Bindings).ViaField("bindings"))
+}
+
+func validateTags(tags []string) *apis.FieldError {
+	if len(tags) == 0 {
+		return apis.ErrMissingField("tags")
+	}
+
+	var errs *apis.FieldError
+	for i, tag := range tags {
+		errs = errs.Also(validateTag(tag, fmt.Sprintf("tags[%d]", i)))
+	}
+	return errs
 }
@@ -0,0 +1,113 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/knative/pkg/apis"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildValidation(t *testing.T) {
+	spec.Run(t, "Build Validation", testBuildValidation)
+}
+
+func testBuildValidation(t *testing.T, when spec.G, it spec.S) {
+	build := &Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "build-name",
+		},
+		Spec: BuildSpec{
+			Tags: []string{"some/image", "some/image:1"},
+			Builder: BuilderImage{
+				Image: "builder/bionic-builder@sha256:a62e729e0bc1ce3b1ab1cff8e2e20b08f2a5e3e6b2fe1e0d6fba5c4f1dc6d1b8",
+			},
+			ServiceAccount: "some/service-account",
+			Source: SourceConfig{
+				Registry: &Registry{
+					Image: "some/source-image",
+				},
+			},
+		},
+	}
+
+	when("Default", func() {
+		it("defaults service account", func() {
+			build.Spec.ServiceAccount = ""
+
+			build.SetDefaults(context.TODO())
+
+			assert.Equal(t, "default", build.Spec.ServiceAccount)
+		})
+	})
+
+	when("Validate", func() {
+		it("returns nil on no validation error", func() {
+			assert.Nil(t, build.Validate(context.TODO()))
+		})
+
+		assertValidationError := func(build *Build, expectedError *apis.FieldError) {
+			t.Helper()
+			err := build.Validate(context.TODO())
+			assert.EqualError(t, err, expectedError.Error())
+		}
+
+		it("missing tags", func() {
+			build.Spec.Tags = nil
+			assertValidationError(build, apis.ErrMissingField("tags").ViaField("spec"))
+		})
+
+		it("invalid tag", func() {
+			build.Spec.Tags = append(build.Spec.Tags, "ftp//invalid/tag@@")
+			assertValidationError(build, apis.ErrInvalidValue("ftp//invalid/tag@@", "tags[2]").ViaField("spec"))
+		})
+
+		it("missing builder image", func() {
+			build.Spec.Builder.Image = ""
+			assertValidationError(build, apis.ErrMissingField("image").ViaField("spec", "builder"))
+		})
+
+		it("missing registry image", func() {
+			build.Spec.Source.Registry.Image = ""
+			assertValidationError(build, apis.ErrMissingField("image").ViaField("spec", "source", "registry"))
+		})
+
+		it("allows the resolved blob revision", func() {
+			build.Spec.Source = SourceConfig{
+				Blob: &Blob{
+					URL:      "https://blob.com/app.zip",
+					Revision: "some-etag",
+				},
+			}
+			assert.Nil(t, build.Validate(context.TODO()))
+		})
+
+		it("allows the resolved registry digest", func() {
+			build.Spec.Source.Registry.Digest = "sha256:0ff9a4a5a4a8e1e7c1c8a5a7b39f0a6c4b3a3b2b0e3a6f7d2c1b0a9f8e7d6c5b"
+			assert.Nil(t, build.Validate(context.TODO()))
+		})
+
+		it("allows the resolved git tree hash", func() {
+			build.Spec.Source = SourceConfig{
+				Git: &Git{
+					URL:         "https://github.com/some/repo",
+					Revision:    "some-sha",
+					FilterPaths: true,
+					TreeHash:    "some-tree-hash",
+				},
+			}
+			assert.Nil(t, build.Validate(context.TODO()))
+		})
+
+		it("env var without a name", func() {
+			build.Spec.Env = []corev1.EnvVar{
+				{Name: "keyA", Value: "valueA"},
+				{Value: "valueB"},
+			}
+			assertValidationError(build, apis.ErrMissingField("name").ViaIndex(1).ViaField("spec", "env"))
+		})
+	})
+}
@@ -13,6 +13,9 @@ func (b *Builder) ImageRef() BuilderImage {
 	return BuilderImage{
 		Image:            b.Status.LatestImage,
 		ImagePullSecrets: b.Spec.ImagePullSecrets,
+		RunImage:         b.Status.Stack.RunImage,
+		StackID:          b.Status.Stack.ID,
+		LifecycleVersion: b.Status.LifecycleVersion,
 	}
 }
 
@@ -46,3 +49,8 @@ func (b *Builder) BuildpackMetadata() BuildpackMetadataList {
 func (b *Builder) GetName() string {
 	return b.ObjectMeta.Name
 }
+
+// RefreshFailed reports whether the last attempt to resolve the builder image failed.
+func (s *BuilderStatus) RefreshFailed() bool {
+	return s.GetCondition(ConditionUpToDate).IsFalse()
+}
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"github.com/knative/pkg/apis"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -20,6 +21,11 @@ type Builder struct {
 	Status BuilderStatus          `json:"status"`
 }
 
+var (
+	_ apis.Validatable = (*Builder)(nil)
+	_ apis.Defaultable = (*Builder)(nil)
+)
+
 type BuilderSpec struct {
 	Image        string              `json:"image"`
 	UpdatePolicy BuilderUpdatePolicy `json:"updatePolicy"`
@@ -37,10 +43,36 @@ const (
 	External BuilderUpdatePolicy = "external"
 )
 
+const (
+	ConditionUpToDate duckv1alpha1.ConditionType = "UpToDate"
+
+	BuilderRefreshFailed = "RefreshFailed"
+)
+
 type BuilderStatus struct {
 	duckv1alpha1.Status `json:",inline"`
 	BuilderMetadata     BuildpackMetadataList `json:"builderMetadata"`
 	LatestImage         string                `json:"latestImage"`
+	Stack               BuilderStackStatus    `json:"stack,omitempty"`
+	LifecycleVersion    string                `json:"lifecycleVersion,omitempty"`
+	Order               []BuilderOrderGroup   `json:"order,omitempty"`
+}
+
+type BuilderStackStatus struct {
+	ID              string   `json:"id,omitempty"`
+	BuildImage      string   `json:"buildImage,omitempty"`
+	RunImage        string   `json:"runImage,omitempty"`
+	RunImageMirrors []string `json:"runImageMirrors,omitempty"`
+}
+
+type BuilderOrderGroup struct {
+	Group []BuilderOrderBuildpack `json:"group"`
+}
+
+type BuilderOrderBuildpack struct {
+	ID       string `json:"id"`
+	Version  string `json:"version"`
+	Optional bool   `json:"optional,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -0,0 +1,45 @@
+/*
+ * Copyright 2019 The original author or authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/knative/pkg/apis"
+)
+
+func (b *Builder) Validate(ctx context.Context) *apis.FieldError {
+	return b.Spec.Validate(ctx).ViaField("spec")
+}
+
+func (cb *ClusterBuilder) Validate(ctx context.Context) *apis.FieldError {
+	return cb.Spec.Validate(ctx).ViaField("spec")
+}
+
+func (bs *BuilderSpec) Validate(ctx context.Context) *apis.FieldError {
+	return validateBuilderImage(bs.Image).
+		Also(bs.validateUpdatePolicy())
+}
+
+func (bs *BuilderSpec) validateUpdatePolicy() *apis.FieldError {
+	switch bs.UpdatePolicy {
+	case Polling, External:
+		return nil
+	default:
+		return apis.ErrInvalidValue(string(bs.UpdatePolicy), "updatePolicy")
+	}
+}
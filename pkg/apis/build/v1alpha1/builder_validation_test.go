@@ -0,0 +1,55 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/knative/pkg/apis"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuilderValidation(t *testing.T) {
+	spec.Run(t, "Builder Validation", testBuilderValidation)
+}
+
+func testBuilderValidation(t *testing.T, when spec.G, it spec.S) {
+	builder := &Builder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "builder-name",
+		},
+		Spec: BuilderWithSecretsSpec{
+			BuilderSpec: BuilderSpec{
+				Image:        "some/builder",
+				UpdatePolicy: External,
+			},
+		},
+	}
+
+	when("Default", func() {
+		it("defaults the update policy to polling", func() {
+			builder.Spec.UpdatePolicy = ""
+
+			builder.SetDefaults(context.TODO())
+
+			assert.Equal(t, Polling, builder.Spec.UpdatePolicy)
+		})
+	})
+
+	when("Validate", func() {
+		it("returns nil on no validation error", func() {
+			assert.Nil(t, builder.Validate(context.TODO()))
+		})
+
+		it("missing image", func() {
+			builder.Spec.Image = ""
+			assert.EqualError(t, builder.Validate(context.TODO()), apis.ErrMissingField("image").ViaField("spec").Error())
+		})
+
+		it("invalid update policy", func() {
+			builder.Spec.UpdatePolicy = "sometimes"
+			assert.EqualError(t, builder.Validate(context.TODO()), apis.ErrInvalidValue("sometimes", "updatePolicy").ViaField("spec").Error())
+		})
+	})
+}
@@ -28,6 +28,9 @@ func (in *ClusterBuilder) ImageRef() BuilderImage {
 	return BuilderImage{
 		Image:            in.Status.LatestImage,
 		ImagePullSecrets: nil,
+		RunImage:         in.Status.Stack.RunImage,
+		StackID:          in.Status.Stack.ID,
+		LifecycleVersion: in.Status.LifecycleVersion,
 	}
 }
 
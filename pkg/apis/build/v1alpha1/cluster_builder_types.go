@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"github.com/knative/pkg/apis"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -20,6 +21,11 @@ type ClusterBuilder struct {
 	Status BuilderStatus `json:"status"`
 }
 
+var (
+	_ apis.Validatable = (*ClusterBuilder)(nil)
+	_ apis.Defaultable = (*ClusterBuilder)(nil)
+)
+
 // +genclient:nonNamespaced
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -18,9 +18,11 @@ const (
 	ImageLabel       = "image.build.pivotal.io/image"
 
 	BuildReasonAnnotation = "image.build.pivotal.io/reason"
+	CacheResetAnnotation  = "image.build.pivotal.io/resetCache"
 	BuildReasonConfig     = "CONFIG"
 	BuildReasonCommit     = "COMMIT"
 	BuildReasonBuildpack  = "BUILDPACK"
+	BuildReasonStack      = "STACK"
 )
 
 type AbstractBuilder interface {
@@ -52,7 +54,9 @@ func (im *Image) buildNeeded(lastBuild *Build, sourceResolver *SourceResolver, b
 
 	if sourceResolver.ConfigChanged(lastBuild) ||
 		!equality.Semantic.DeepEqual(im.Spec.Build.Env, lastBuild.Spec.Env) ||
-		!equality.Semantic.DeepEqual(im.Spec.Build.Resources, lastBuild.Spec.Resources) {
+		!equality.Semantic.DeepEqual(im.Spec.Build.Resources, lastBuild.Spec.Resources) ||
+		!equality.Semantic.DeepEqual(im.Spec.Build.Bindings, lastBuild.Spec.Bindings) ||
+		im.schedulingChanged(lastBuild) {
 		reasons = append(reasons, BuildReasonConfig)
 	}
 
@@ -64,9 +68,35 @@ func (im *Image) buildNeeded(lastBuild *Build, sourceResolver *SourceResolver, b
 		reasons = append(reasons, BuildReasonBuildpack)
 	}
 
+	if !lastBuildBuiltWithBuilderRunImage(builder, lastBuild) {
+		reasons = append(reasons, BuildReasonStack)
+	}
+
 	return reasons, len(reasons) > 0
 }
 
+func lastBuildBuiltWithBuilderRunImage(builder AbstractBuilder, build *Build) bool {
+	if build.Status.Stack.RunImage == "" || builder.ImageRef().RunImage == "" {
+		return true
+	}
+
+	return build.Status.Stack.RunImage == builder.ImageRef().RunImage
+}
+
+// rebaseOnly reports whether the last build can be rebased onto the new run image
+// instead of running a full build.
+func rebaseOnly(lastBuild *Build, reasons []string) bool {
+	return lastBuild.IsSuccess() && len(reasons) == 1 && reasons[0] == BuildReasonStack
+}
+
+func (im *Image) schedulingChanged(lastBuild *Build) bool {
+	return !equality.Semantic.DeepEqual(im.Spec.Build.NodeSelector, lastBuild.Spec.NodeSelector) ||
+		!equality.Semantic.DeepEqual(im.Spec.Build.Tolerations, lastBuild.Spec.Tolerations) ||
+		!equality.Semantic.DeepEqual(im.Spec.Build.Affinity, lastBuild.Spec.Affinity) ||
+		im.Spec.Build.PriorityClassName != lastBuild.Spec.PriorityClassName ||
+		!equality.Semantic.DeepEqual(im.Spec.Build.RuntimeClassName, lastBuild.Spec.RuntimeClassName)
+}
+
 func lastBuildBuiltWithBuilderBuildpacks(builder AbstractBuilder, build *Build) bool {
 	for _, bp := range build.Status.BuildMetadata {
 		if !builder.BuildpackMetadata().Include(bp) {
@@ -77,8 +107,17 @@ func lastBuildBuiltWithBuilderBuildpacks(builder AbstractBuilder, build *Build)
 	return true
 }
 
-func (im *Image) build(sourceResolver *SourceResolver, builder AbstractBuilder, reasons []string, nextBuildNumber int64) *Build {
+func (im *Image) build(lastBuild *Build, sourceResolver *SourceResolver, builder AbstractBuilder, reasons []string, nextBuildNumber int64) *Build {
 	buildNumber := strconv.Itoa(int(nextBuildNumber))
+
+	var rebase *LastBuild
+	if rebaseOnly(lastBuild, reasons) {
+		rebase = &LastBuild{
+			Image:   lastBuild.BuiltImage(),
+			StackID: lastBuild.Status.Stack.ID,
+		}
+	}
+
 	return &Build{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:    im.Namespace,
@@ -95,13 +134,22 @@ func (im *Image) build(sourceResolver *SourceResolver, builder AbstractBuilder,
 			},
 		},
 		Spec: BuildSpec{
-			Tags:           im.generateTags(buildNumber),
-			Builder:        builder.ImageRef(),
-			Env:            im.Spec.Build.Env,
-			Resources:      im.Spec.Build.Resources,
-			ServiceAccount: im.Spec.ServiceAccount,
-			Source:         sourceResolver.SourceConfig(),
-			CacheName:      im.Status.BuildCacheName,
+			Tags:              im.generateTags(buildNumber),
+			Builder:           builder.ImageRef(),
+			Env:               im.Spec.Build.Env,
+			Resources:         im.Spec.Build.Resources,
+			Timeout:           im.Spec.Build.Timeout,
+			NodeSelector:      im.Spec.Build.NodeSelector,
+			Tolerations:       im.Spec.Build.Tolerations,
+			Affinity:          im.Spec.Build.Affinity,
+			PriorityClassName: im.Spec.Build.PriorityClassName,
+			RuntimeClassName:  im.Spec.Build.RuntimeClassName,
+			Bindings:          im.Spec.Build.Bindings,
+			ServiceAccount:    im.Spec.ServiceAccount,
+			Source:            sourceResolver.SourceConfig(),
+			CacheName:         im.Status.BuildCacheName,
+			CacheImage:        im.Spec.CacheImage,
+			LastBuild:         rebase,
 		},
 	}
 }
@@ -123,6 +171,21 @@ func (im *Image) NeedCache() bool {
 }
 
 func (im *Image) BuildCache() *corev1.PersistentVolumeClaim {
+	accessMode := im.Spec.CacheAccessMode
+	if accessMode == "" {
+		accessMode = corev1.ReadWriteOnce
+	}
+
+	var storageClassName *string
+	if im.Spec.CacheStorageClassName != "" {
+		storageClassName = &im.Spec.CacheStorageClassName
+	}
+
+	var annotations map[string]string
+	if reset := im.Annotations[CacheResetAnnotation]; reset != "" {
+		annotations = map[string]string{CacheResetAnnotation: reset}
+	}
+
 	return &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      im.CacheName(),
@@ -130,10 +193,12 @@ func (im *Image) BuildCache() *corev1.PersistentVolumeClaim {
 			OwnerReferences: []metav1.OwnerReference{
 				*kmeta.NewControllerRef(im),
 			},
-			Labels: im.labels(nil),
+			Labels:      im.labels(nil),
+			Annotations: annotations,
 		},
 		Spec: corev1.PersistentVolumeClaimSpec{
-			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			AccessModes:      []corev1.PersistentVolumeAccessMode{accessMode},
+			StorageClassName: storageClassName,
 			Resources: corev1.ResourceRequirements{
 				Requests: corev1.ResourceList{
 					corev1.ResourceStorage: *im.Spec.CacheSize,
@@ -143,6 +208,29 @@ func (im *Image) BuildCache() *corev1.PersistentVolumeClaim {
 	}
 }
 
+// BuildCacheRecreateNeeded reports whether the existing build cache must be replaced because
+// the requested change cannot be applied in place: a different storage class or access mode,
+// a smaller size or a new reset annotation.
+func (im *Image) BuildCacheRecreateNeeded(buildCache *corev1.PersistentVolumeClaim) bool {
+	desired := im.BuildCache()
+
+	if desired.Spec.StorageClassName != nil &&
+		(buildCache.Spec.StorageClassName == nil || *desired.Spec.StorageClassName != *buildCache.Spec.StorageClassName) {
+		return true
+	}
+
+	if !equality.Semantic.DeepEqual(desired.Spec.AccessModes, buildCache.Spec.AccessModes) {
+		return true
+	}
+
+	size := buildCache.Spec.Resources.Requests[corev1.ResourceStorage]
+	if im.Spec.CacheSize.Cmp(size) < 0 {
+		return true
+	}
+
+	return desired.Annotations[CacheResetAnnotation] != buildCache.Annotations[CacheResetAnnotation]
+}
+
 func (im *Image) SourceResolverName() string {
 	return kmeta.ChildName(im.Name, "-source")
 }
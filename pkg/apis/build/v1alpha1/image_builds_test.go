@@ -168,6 +168,26 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 				assert.Contains(t, reasons, BuildReasonCommit)
 			})
 
+			it("false for a different GitRevision with the same tree hash", func() {
+				sourceResolver.Status.Source.Git.Revision = "different"
+				sourceResolver.Status.Source.Git.TreeHash = "some-tree-hash"
+				build.Spec.Source.Git.TreeHash = "some-tree-hash"
+
+				_, needed := image.buildNeeded(build, sourceResolver, builder)
+				assert.False(t, needed)
+			})
+
+			it("true for a different tree hash", func() {
+				sourceResolver.Status.Source.Git.Revision = "different"
+				sourceResolver.Status.Source.Git.TreeHash = "different-tree-hash"
+				build.Spec.Source.Git.TreeHash = "some-tree-hash"
+
+				reasons, needed := image.buildNeeded(build, sourceResolver, builder)
+				assert.True(t, needed)
+				require.Len(t, reasons, 1)
+				assert.Contains(t, reasons, BuildReasonCommit)
+			})
+
 			it("false if source resolver is not ready", func() {
 				sourceResolver.Status.Source.Git.Revision = "different"
 				sourceResolver.Status.Conditions = []duckv1alpha1.Condition{
@@ -310,6 +330,41 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 				assert.Contains(t, reasons, BuildReasonConfig)
 			})
 
+			it("true if build scheduling changes", func() {
+				image.Spec.Build.NodeSelector = map[string]string{"pool": "builds"}
+				image.Spec.Build.Tolerations = []v1.Toleration{
+					{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "builds", Effect: v1.TaintEffectNoSchedule},
+				}
+
+				reasons, needed := image.buildNeeded(build, sourceResolver, builder)
+				assert.True(t, needed)
+				require.Len(t, reasons, 1)
+				assert.Contains(t, reasons, BuildReasonConfig)
+			})
+
+			it("true if build bindings change", func() {
+				image.Spec.Build.Bindings = []Binding{
+					{
+						Name:      "apm",
+						SecretRef: v1.LocalObjectReference{Name: "apm-secret"},
+					},
+				}
+
+				reasons, needed := image.buildNeeded(build, sourceResolver, builder)
+				assert.True(t, needed)
+				require.Len(t, reasons, 1)
+				assert.Contains(t, reasons, BuildReasonConfig)
+			})
+
+			it("true if build priority class changes", func() {
+				image.Spec.Build.PriorityClassName = "builds"
+
+				reasons, needed := image.buildNeeded(build, sourceResolver, builder)
+				assert.True(t, needed)
+				require.Len(t, reasons, 1)
+				assert.Contains(t, reasons, BuildReasonConfig)
+			})
+
 			when("Builder Metadata changes", func() {
 				it("false if builder has additional unused buildpack metadata", func() {
 					builder.Status.BuilderMetadata = []BuildpackMetadata{
@@ -346,6 +401,24 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 					assert.Contains(t, reasons, BuildReasonBuildpack)
 				})
 
+				it("true if the builder run image differs from the run image of the last build", func() {
+					builder.Status.Stack.RunImage = "some/run@sha256:new-run-digest"
+					build.Status.Stack.RunImage = "some/run@sha256:old-run-digest"
+
+					reasons, needed := image.buildNeeded(build, sourceResolver, builder)
+					assert.True(t, needed)
+					require.Len(t, reasons, 1)
+					assert.Contains(t, reasons, BuildReasonStack)
+				})
+
+				it("false if the last build has no recorded run image", func() {
+					builder.Status.Stack.RunImage = "some/run@sha256:new-run-digest"
+
+					reasons, needed := image.buildNeeded(build, sourceResolver, builder)
+					assert.False(t, needed)
+					require.Len(t, reasons, 0)
+				})
+
 				it("true if both config and commit have changed", func() {
 					sourceResolver.Status.Source.Git.URL = "different"
 					sourceResolver.Status.Source.Git.Revision = "different"
@@ -389,6 +462,26 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 				require.Len(t, reasons, 1)
 				assert.Contains(t, reasons, BuildReasonConfig)
 			})
+
+			it("true for different Blob revision", func() {
+				sourceResolver.Status.Source.Blob.URL = "some-url"
+				sourceResolver.Status.Source.Blob.Revision = `"new-etag"`
+				build.Spec.Source.Blob.Revision = `"old-etag"`
+
+				reasons, needed := image.buildNeeded(build, sourceResolver, builder)
+				assert.True(t, needed)
+				require.Len(t, reasons, 1)
+				assert.Contains(t, reasons, BuildReasonCommit)
+			})
+
+			it("false for the same Blob revision", func() {
+				sourceResolver.Status.Source.Blob.URL = "some-url"
+				sourceResolver.Status.Source.Blob.Revision = `"some-etag"`
+				build.Spec.Source.Blob.Revision = `"some-etag"`
+
+				_, needed := image.buildNeeded(build, sourceResolver, builder)
+				assert.False(t, needed)
+			})
 		})
 
 		when("Registry", func() {
@@ -421,6 +514,17 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 				require.Len(t, reasons, 1)
 				assert.Contains(t, reasons, BuildReasonConfig)
 			})
+
+			it("true for a different Registry digest", func() {
+				sourceResolver.Status.Source.Registry.Image = "some-image"
+				sourceResolver.Status.Source.Registry.Digest = "sha256:new-digest"
+				build.Spec.Source.Registry.Digest = "sha256:old-digest"
+
+				reasons, needed := image.buildNeeded(build, sourceResolver, builder)
+				assert.True(t, needed)
+				require.Len(t, reasons, 1)
+				assert.Contains(t, reasons, BuildReasonCommit)
+			})
 		})
 	})
 
@@ -445,7 +549,7 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 		it("generates a build name with build number", func() {
 			image.Name = "imageName"
 
-			build := image.build(sourceResolver, builder, []string{}, 27)
+			build := image.build(nil, sourceResolver, builder, []string{}, 27)
 
 			assert.Contains(t, build.GenerateName, "imageName-build-27-")
 		})
@@ -453,13 +557,13 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 		it("sets builder to be the Builder's resolved latestImage", func() {
 			image.Name = "imageName"
 
-			build := image.build(sourceResolver, builder, []string{}, 27)
+			build := image.build(nil, sourceResolver, builder, []string{}, 27)
 
 			assert.Equal(t, builder.Status.LatestImage, build.Spec.Builder.Image)
 		})
 
 		it("sets git url and git revision when image source is git", func() {
-			build := image.build(sourceResolver, builder, []string{}, 27)
+			build := image.build(nil, sourceResolver, builder, []string{}, 27)
 
 			assert.Contains(t, build.Spec.Source.Git.URL, "https://some.git/url")
 			assert.Contains(t, build.Spec.Source.Git.Revision, "revision")
@@ -467,50 +571,62 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 			assert.Nil(t, build.Spec.Source.Registry)
 		})
 
-		it("sets blob url when image source is blob", func() {
+		it("sets blob url and revision when image source is blob", func() {
 			sourceResolver.Status.Source = ResolvedSourceConfig{
 				Blob: &ResolvedBlobSource{
-					URL: "https://some.place/blob.jar",
+					URL:      "https://some.place/blob.jar",
+					Revision: `"some-etag"`,
 				},
 			}
-			build := image.build(sourceResolver, builder, []string{}, 27)
+			build := image.build(nil, sourceResolver, builder, []string{}, 27)
 
 			assert.Nil(t, build.Spec.Source.Git)
 			assert.Nil(t, build.Spec.Source.Registry)
 			assert.Equal(t, build.Spec.Source.Blob.URL, "https://some.place/blob.jar")
+			assert.Equal(t, build.Spec.Source.Blob.Revision, `"some-etag"`)
 		})
 
-		it("sets registry image when image source is registry", func() {
+		it("sets registry image and digest when image source is registry", func() {
 			sourceResolver.Status.Source = ResolvedSourceConfig{
 				Registry: &ResolvedRegistrySource{
-					Image: "some-registry.io/some-image",
+					Image:  "some-registry.io/some-image",
+					Digest: "sha256:some-digest",
 				},
 			}
-			build := image.build(sourceResolver, builder, []string{}, 27)
+			build := image.build(nil, sourceResolver, builder, []string{}, 27)
 
 			assert.Nil(t, build.Spec.Source.Git)
 			assert.Nil(t, build.Spec.Source.Blob)
 			assert.Equal(t, build.Spec.Source.Registry.Image, "some-registry.io/some-image")
+			assert.Equal(t, build.Spec.Source.Registry.Digest, "sha256:some-digest")
+		})
+
+		it("sets the cache image", func() {
+			image := image.DeepCopy()
+			image.Spec.CacheImage = "some/image:cache"
+			build := image.build(nil, sourceResolver, builder, []string{}, 27)
+
+			assert.Equal(t, "some/image:cache", build.Spec.CacheImage)
 		})
 
 		it("with excludes additional tags names when explicitly disabled", func() {
 			image.Spec.Tag = "imagename/foo:test"
 			image.Spec.ImageTaggingStrategy = None
-			build := image.build(sourceResolver, builder, []string{BuildReasonConfig}, 1)
+			build := image.build(nil, sourceResolver, builder, []string{BuildReasonConfig}, 1)
 			require.Len(t, build.Spec.Tags, 1)
 		})
 
 		when("generates additional image names for a provided build number", func() {
 			it("with tag prefix if image name has a tag", func() {
 				image.Spec.Tag = "gcr.io/imagename/foo:test"
-				build := image.build(sourceResolver, builder, []string{BuildReasonConfig}, 45)
+				build := image.build(nil, sourceResolver, builder, []string{BuildReasonConfig}, 45)
 				require.Len(t, build.Spec.Tags, 2)
 				require.Regexp(t, "gcr.io/imagename/foo:test-b45\\.\\d{8}\\.\\d{6}", build.Spec.Tags[1])
 			})
 
 			it("without tag prefix if image name has no provided tag", func() {
 				image.Spec.Tag = "gcr.io/imagename/notags"
-				build := image.build(sourceResolver, builder, []string{BuildReasonConfig}, 1)
+				build := image.build(nil, sourceResolver, builder, []string{BuildReasonConfig}, 1)
 
 				require.Len(t, build.Spec.Tags, 2)
 				require.Regexp(t, "gcr.io/imagename/notags:b1\\.\\d{8}\\.\\d{6}", build.Spec.Tags[1])
@@ -518,7 +634,7 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 
 			it("without tag prefix if image name has the tag 'latest' provided", func() {
 				image.Spec.Tag = "gcr.io/imagename/tagged:latest"
-				build := image.build(sourceResolver, builder, []string{BuildReasonConfig}, 1)
+				build := image.build(nil, sourceResolver, builder, []string{BuildReasonConfig}, 1)
 
 				require.Len(t, build.Spec.Tags, 2)
 				require.Regexp(t, "gcr.io/imagename/tagged:b1\\.\\d{8}\\.\\d{6}", build.Spec.Tags[1])
@@ -528,24 +644,121 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 		it("generates a build name less than 64 characters", func() {
 			image.Name = "long-image-name-1234567890-1234567890-1234567890-1234567890-1234567890"
 
-			build := image.build(sourceResolver, builder, []string{BuildReasonConfig}, 1)
+			build := image.build(nil, sourceResolver, builder, []string{BuildReasonConfig}, 1)
 
 			assert.True(t, len(build.Name) < 64, "expected %s to be less than 64", build.Name)
 			assert.True(t, len(build.Name) < 64, "expected %s to be less than 64", build.Name)
 		})
 
 		it("adds the env vars to the build spec", func() {
-			build := image.build(sourceResolver, builder, []string{BuildReasonConfig}, 1)
+			build := image.build(nil, sourceResolver, builder, []string{BuildReasonConfig}, 1)
 
 			assert.Equal(t, image.Spec.Build.Env, build.Spec.Env)
 		})
 
 		it("adds build reasons annotation", func() {
-			build := image.build(sourceResolver, builder, []string{BuildReasonConfig, BuildReasonCommit}, 1)
+			build := image.build(nil, sourceResolver, builder, []string{BuildReasonConfig, BuildReasonCommit}, 1)
 
 			assert.Equal(t, "CONFIG,COMMIT", build.Annotations[BuildReasonAnnotation])
 		})
 
+		it("adds build bindings", func() {
+			image.Spec.Build.Bindings = []Binding{
+				{
+					Name:        "apm",
+					MetadataRef: &v1.LocalObjectReference{Name: "apm-metadata"},
+					SecretRef:   v1.LocalObjectReference{Name: "apm-secret"},
+				},
+			}
+
+			build := image.build(nil, sourceResolver, builder, []string{BuildReasonConfig}, 1)
+
+			assert.Equal(t, image.Spec.Build.Bindings, build.Spec.Bindings)
+		})
+
+		it("adds build scheduling", func() {
+			runtimeClassName := "gvisor"
+			image.Spec.Build.NodeSelector = map[string]string{"pool": "builds"}
+			image.Spec.Build.Tolerations = []v1.Toleration{
+				{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "builds", Effect: v1.TaintEffectNoSchedule},
+			}
+			image.Spec.Build.Affinity = &v1.Affinity{
+				NodeAffinity: &v1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+						NodeSelectorTerms: []v1.NodeSelectorTerm{
+							{
+								MatchExpressions: []v1.NodeSelectorRequirement{
+									{Key: "pool", Operator: v1.NodeSelectorOpIn, Values: []string{"builds"}},
+								},
+							},
+						},
+					},
+				},
+			}
+			image.Spec.Build.PriorityClassName = "builds"
+			image.Spec.Build.RuntimeClassName = &runtimeClassName
+
+			build := image.build(nil, sourceResolver, builder, []string{BuildReasonConfig}, 1)
+
+			assert.Equal(t, image.Spec.Build.NodeSelector, build.Spec.NodeSelector)
+			assert.Equal(t, image.Spec.Build.Tolerations, build.Spec.Tolerations)
+			assert.Equal(t, image.Spec.Build.Affinity, build.Spec.Affinity)
+			assert.Equal(t, "builds", build.Spec.PriorityClassName)
+			assert.Equal(t, &runtimeClassName, build.Spec.RuntimeClassName)
+		})
+
+		when("the run image is the only change", func() {
+			it.Before(func() {
+				builder.Status.Stack.RunImage = "some/run@sha256:new-run-digest"
+				build.Status = BuildStatus{
+					Status: duckv1alpha1.Status{
+						Conditions: duckv1alpha1.Conditions{
+							{
+								Type:   duckv1alpha1.ConditionSucceeded,
+								Status: corev1.ConditionTrue,
+							},
+						},
+					},
+					LatestImage: "some/image@sha256:last-built-digest",
+					Stack: BuildStack{
+						RunImage: "some/run@sha256:old-run-digest",
+						ID:       "io.buildpacks.stacks.bionic",
+					},
+				}
+			})
+
+			it("rebases the last built image", func() {
+				build := image.build(build, sourceResolver, builder, []string{BuildReasonStack}, 2)
+
+				require.NotNil(t, build.Spec.LastBuild)
+				assert.Equal(t, &LastBuild{
+					Image:   "some/image@sha256:last-built-digest",
+					StackID: "io.buildpacks.stacks.bionic",
+				}, build.Spec.LastBuild)
+				assert.Equal(t, "some/run@sha256:new-run-digest", build.Spec.Builder.RunImage)
+				assert.Equal(t, "STACK", build.Annotations[BuildReasonAnnotation])
+			})
+
+			it("does not rebase when there are other reasons to build", func() {
+				build := image.build(build, sourceResolver, builder, []string{BuildReasonCommit, BuildReasonStack}, 2)
+
+				assert.Nil(t, build.Spec.LastBuild)
+			})
+
+			it("does not rebase when the last build failed", func() {
+				build.Status.Conditions = duckv1alpha1.Conditions{
+					{
+						Type:   duckv1alpha1.ConditionSucceeded,
+						Status: corev1.ConditionFalse,
+					},
+				}
+
+				build := image.build(build, sourceResolver, builder, []string{BuildReasonStack}, 2)
+
+				assert.Nil(t, build.Spec.LastBuild)
+			})
+		})
+
 		it("adds build resources", func() {
 			image.Spec.Build.Resources = v1.ResourceRequirements{
 				Limits: v1.ResourceList{
@@ -558,9 +771,109 @@ func testImageBuilds(t *testing.T, when spec.G, it spec.S) {
 				},
 			}
 
-			build := image.build(sourceResolver, builder, []string{BuildReasonConfig}, 1)
+			build := image.build(nil, sourceResolver, builder, []string{BuildReasonConfig}, 1)
 
 			assert.Equal(t, image.Spec.Build.Resources, build.Spec.Resources)
 		})
 	})
+
+	when("#BuildCache", func() {
+		cacheSize := resource.MustParse("1.5")
+
+		it.Before(func() {
+			image.Spec.CacheSize = &cacheSize
+		})
+
+		it("uses the configured storage class and access mode", func() {
+			image.Spec.CacheStorageClassName = "some-storage-class"
+			image.Spec.CacheAccessMode = corev1.ReadWriteMany
+
+			buildCache := image.BuildCache()
+
+			assert.Equal(t, "some-storage-class", *buildCache.Spec.StorageClassName)
+			assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, buildCache.Spec.AccessModes)
+		})
+
+		it("defaults to a ReadWriteOnce cache with the default storage class", func() {
+			buildCache := image.BuildCache()
+
+			assert.Nil(t, buildCache.Spec.StorageClassName)
+			assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, buildCache.Spec.AccessModes)
+		})
+
+		when("#BuildCacheRecreateNeeded", func() {
+			it("false for an unchanged or larger cache", func() {
+				buildCache := image.BuildCache()
+				assert.False(t, image.BuildCacheRecreateNeeded(buildCache))
+
+				largerCacheSize := resource.MustParse("2.5")
+				image.Spec.CacheSize = &largerCacheSize
+				assert.False(t, image.BuildCacheRecreateNeeded(buildCache))
+			})
+
+			it("false when the default storage class was assigned", func() {
+				buildCache := image.BuildCache()
+				defaultStorageClass := "standard"
+				buildCache.Spec.StorageClassName = &defaultStorageClass
+
+				assert.False(t, image.BuildCacheRecreateNeeded(buildCache))
+			})
+
+			it("true for a smaller cache", func() {
+				buildCache := image.BuildCache()
+
+				smallerCacheSize := resource.MustParse("1")
+				image.Spec.CacheSize = &smallerCacheSize
+				assert.True(t, image.BuildCacheRecreateNeeded(buildCache))
+			})
+
+			it("true for a different storage class or access mode", func() {
+				buildCache := image.BuildCache()
+
+				image.Spec.CacheStorageClassName = "some-storage-class"
+				assert.True(t, image.BuildCacheRecreateNeeded(buildCache))
+
+				image.Spec.CacheStorageClassName = ""
+				image.Spec.CacheAccessMode = corev1.ReadWriteMany
+				assert.True(t, image.BuildCacheRecreateNeeded(buildCache))
+			})
+
+			it("true when the cache is reset", func() {
+				buildCache := image.BuildCache()
+
+				image.Annotations = map[string]string{CacheResetAnnotation: "some-reset"}
+				assert.True(t, image.BuildCacheRecreateNeeded(buildCache))
+				assert.False(t, image.BuildCacheRecreateNeeded(image.BuildCache()))
+			})
+		})
+
+		when("#BuildCacheConditions", func() {
+			it("does not report a bound cache", func() {
+				buildCache := image.BuildCache()
+				buildCache.Status.Phase = corev1.ClaimBound
+
+				assert.Nil(t, image.BuildCacheConditions(buildCache))
+			})
+
+			it("reports a resizing cache", func() {
+				buildCache := image.BuildCache()
+				buildCache.Status.Phase = corev1.ClaimBound
+				buildCache.Status.Conditions = []corev1.PersistentVolumeClaimCondition{
+					{
+						Type:   corev1.PersistentVolumeClaimFileSystemResizePending,
+						Status: corev1.ConditionTrue,
+					},
+				}
+
+				assert.Equal(t, duckv1alpha1.Conditions{
+					{
+						Type:    ConditionCacheReady,
+						Status:  corev1.ConditionUnknown,
+						Reason:  CacheResizing,
+						Message: "Build cache image-name-cache is being resized.",
+					},
+				}, image.BuildCacheConditions(buildCache))
+			})
+		})
+	})
 }
@@ -0,0 +1,37 @@
+/*
+ * Copyright 2019 The original author or authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import "context"
+
+func (i *Image) SetDefaults(ctx context.Context) {
+	i.Spec.SetDefaults(ctx)
+}
+
+func (is *ImageSpec) SetDefaults(ctx context.Context) {
+	if is.ServiceAccount == "" {
+		is.ServiceAccount = defaultServiceAccount
+	}
+
+	if is.ImageTaggingStrategy == "" {
+		is.ImageTaggingStrategy = BuildNumber
+	}
+
+	if is.Builder.Kind == "" {
+		is.Builder.Kind = BuilderKind
+	}
+}
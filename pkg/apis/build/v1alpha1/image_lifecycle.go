@@ -10,6 +10,13 @@ import (
 const (
 	BuilderNotFound = "BuilderNotFound"
 	BuilderNotReady = "BuilderNotReady"
+
+	ConditionCacheReady = "CacheReady"
+	CachePending        = "CachePending"
+	CacheLost           = "CacheLost"
+	CacheResizing       = "CacheResizing"
+	CacheResizeFailed   = "CacheResizeFailed"
+	CacheRecreating     = "CacheRecreating"
 )
 
 func (im *Image) BuilderNotFound() duckv1alpha1.Conditions {
@@ -22,3 +29,53 @@ func (im *Image) BuilderNotFound() duckv1alpha1.Conditions {
 		},
 	}
 }
+
+// BuildCacheConditions reports a build cache that is not ready to be used by builds.
+// A ready build cache is not reported.
+func (im *Image) BuildCacheConditions(buildCache *corev1.PersistentVolumeClaim) duckv1alpha1.Conditions {
+	switch buildCache.Status.Phase {
+	case corev1.ClaimPending:
+		return cacheNotReady(corev1.ConditionFalse, CachePending, fmt.Sprintf("Build cache %s is pending.", buildCache.Name))
+	case corev1.ClaimLost:
+		return cacheNotReady(corev1.ConditionFalse, CacheLost, fmt.Sprintf("Build cache %s has lost its volume.", buildCache.Name))
+	}
+
+	for _, condition := range buildCache.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case corev1.PersistentVolumeClaimResizing, corev1.PersistentVolumeClaimFileSystemResizePending:
+			return cacheNotReady(corev1.ConditionUnknown, CacheResizing, fmt.Sprintf("Build cache %s is being resized.", buildCache.Name))
+		}
+	}
+	return nil
+}
+
+func (im *Image) BuildCacheResizeFailed(err error) duckv1alpha1.Conditions {
+	return cacheNotReady(corev1.ConditionFalse, CacheResizeFailed, fmt.Sprintf("Unable to resize build cache %s: %s", im.CacheName(), err))
+}
+
+// BuildCacheRecreating keeps the current image conditions while the build cache is replaced.
+func (im *Image) BuildCacheRecreating() duckv1alpha1.Conditions {
+	var conditions duckv1alpha1.Conditions
+	for _, condition := range im.Status.Conditions {
+		if condition.Type != ConditionCacheReady {
+			conditions = append(conditions, condition)
+		}
+	}
+
+	return append(conditions, cacheNotReady(corev1.ConditionUnknown, CacheRecreating, fmt.Sprintf("Build cache %s is being recreated.", im.CacheName()))...)
+}
+
+func cacheNotReady(status corev1.ConditionStatus, reason, message string) duckv1alpha1.Conditions {
+	return duckv1alpha1.Conditions{
+		{
+			Type:    ConditionCacheReady,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		},
+	}
+}
@@ -19,9 +19,10 @@ func (im *Image) ReconcileBuild(latestBuild *Build, resolver *SourceResolver, bu
 		nextBuildNumber := currentBuildNumber + 1
 		return newBuild{
 			previousBuild: latestBuild,
-			build:         im.build(resolver, builder, reasons, nextBuildNumber),
+			build:         im.build(latestBuild, resolver, builder, reasons, nextBuildNumber),
 			buildCounter:  nextBuildNumber,
 			latestImage:   latestImage,
+			resolver:      resolver,
 		}, nil
 	}
 
@@ -30,6 +31,7 @@ func (im *Image) ReconcileBuild(latestBuild *Build, resolver *SourceResolver, bu
 		buildCounter: currentBuildNumber,
 		latestImage:  latestImage,
 		builder:      builder,
+		resolver:     resolver,
 	}, nil
 }
 
@@ -53,6 +55,7 @@ type upToDateBuild struct {
 	buildCounter int64
 	latestImage  string
 	builder      AbstractBuilder
+	resolver     *SourceResolver
 }
 
 func (r upToDateBuild) Apply(creator BuildCreator) (ReconciledBuild, error) {
@@ -66,22 +69,24 @@ func (r upToDateBuild) Apply(creator BuildCreator) (ReconciledBuild, error) {
 
 func (r upToDateBuild) conditions() duckv1alpha1.Conditions {
 	if r.build == nil || r.build.Status.GetCondition(duckv1alpha1.ConditionSucceeded) == nil {
-		return duckv1alpha1.Conditions{
+		return append(duckv1alpha1.Conditions{
 			{
 				Type:   duckv1alpha1.ConditionReady,
 				Status: corev1.ConditionUnknown,
 			}, r.builderCondition(),
-		}
+		}, sourceResolutionConditions(r.resolver)...)
 	}
 
 	condition := r.build.Status.GetCondition(duckv1alpha1.ConditionSucceeded)
 
-	return duckv1alpha1.Conditions{
+	return append(duckv1alpha1.Conditions{
 		{
-			Type:   duckv1alpha1.ConditionReady,
-			Status: condition.Status,
+			Type:    duckv1alpha1.ConditionReady,
+			Status:  condition.Status,
+			Reason:  condition.Reason,
+			Message: condition.Message,
 		}, r.builderCondition(),
-	}
+	}, sourceResolutionConditions(r.resolver)...)
 }
 
 func (r upToDateBuild) builderCondition() duckv1alpha1.Condition {
@@ -104,6 +109,7 @@ type newBuild struct {
 	buildCounter  int64
 	latestImage   string
 	previousBuild *Build
+	resolver      *SourceResolver
 }
 
 func (r newBuild) Apply(creator BuildCreator) (ReconciledBuild, error) {
@@ -117,7 +123,7 @@ func (r newBuild) Apply(creator BuildCreator) (ReconciledBuild, error) {
 }
 
 func (r newBuild) conditions() duckv1alpha1.Conditions {
-	return duckv1alpha1.Conditions{
+	return append(duckv1alpha1.Conditions{
 		{
 			Type:   duckv1alpha1.ConditionReady,
 			Status: corev1.ConditionUnknown,
@@ -126,7 +132,15 @@ func (r newBuild) conditions() duckv1alpha1.Conditions {
 			Type:   ConditionBuilderReady,
 			Status: corev1.ConditionTrue,
 		},
+	}, sourceResolutionConditions(r.resolver)...)
+}
+
+// sourceResolutionConditions carries a failed source resolution into the image status.
+func sourceResolutionConditions(resolver *SourceResolver) duckv1alpha1.Conditions {
+	if !resolver.ResolutionFailed() {
+		return nil
 	}
+	return duckv1alpha1.Conditions{*resolver.Status.GetCondition(ConditionSourceResolved)}
 }
 
 func buildCounter(build *Build) (int64, error) {
@@ -17,6 +17,7 @@
 package v1alpha1
 
 import (
+	"github.com/knative/pkg/apis"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -36,16 +37,24 @@ type Image struct {
 	Status ImageStatus `json:"status"`
 }
 
+var (
+	_ apis.Validatable = (*Image)(nil)
+	_ apis.Defaultable = (*Image)(nil)
+)
+
 type ImageSpec struct {
-	Tag                      string               `json:"tag"`
-	Builder                  ImageBuilder         `json:"builder"`
-	ServiceAccount           string               `json:"serviceAccount"`
-	Source                   SourceConfig         `json:"source"`
-	CacheSize                *resource.Quantity   `json:"cacheSize,omitempty"`
-	FailedBuildHistoryLimit  *int64               `json:"failedBuildHistoryLimit"`
-	SuccessBuildHistoryLimit *int64               `json:"successBuildHistoryLimit"`
-	ImageTaggingStrategy     ImageTaggingStrategy `json:"imageTaggingStrategy"`
-	Build                    ImageBuild           `json:"build"`
+	Tag                      string                            `json:"tag"`
+	Builder                  ImageBuilder                      `json:"builder"`
+	ServiceAccount           string                            `json:"serviceAccount"`
+	Source                   SourceConfig                      `json:"source"`
+	CacheSize                *resource.Quantity                `json:"cacheSize,omitempty"`
+	CacheImage               string                            `json:"cacheImage,omitempty"`
+	CacheStorageClassName    string                            `json:"cacheStorageClassName,omitempty"`
+	CacheAccessMode          corev1.PersistentVolumeAccessMode `json:"cacheAccessMode,omitempty"`
+	FailedBuildHistoryLimit  *int64                            `json:"failedBuildHistoryLimit"`
+	SuccessBuildHistoryLimit *int64                            `json:"successBuildHistoryLimit"`
+	ImageTaggingStrategy     ImageTaggingStrategy              `json:"imageTaggingStrategy"`
+	Build                    ImageBuild                        `json:"build"`
 }
 
 type ImageBuilder struct {
@@ -61,8 +70,15 @@ const (
 )
 
 type ImageBuild struct {
-	Env       []corev1.EnvVar             `json:"env"`
-	Resources corev1.ResourceRequirements `json:"resources"`
+	Env               []corev1.EnvVar             `json:"env"`
+	Resources         corev1.ResourceRequirements `json:"resources"`
+	Timeout           *metav1.Duration            `json:"timeout,omitempty"`
+	NodeSelector      map[string]string           `json:"nodeSelector,omitempty"`
+	Tolerations       []corev1.Toleration         `json:"tolerations,omitempty"`
+	Affinity          *corev1.Affinity            `json:"affinity,omitempty"`
+	PriorityClassName string                      `json:"priorityClassName,omitempty"`
+	RuntimeClassName  *string                     `json:"runtimeClassName,omitempty"`
+	Bindings          []Binding                   `json:"bindings,omitempty"`
 }
 
 type ImageStatus struct {
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2019 The original author or authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func (i *Image) Validate(ctx context.Context) *apis.FieldError {
+	return i.Spec.Validate(ctx).ViaField("spec")
+}
+
+func (is *ImageSpec) Validate(ctx context.Context) *apis.FieldError {
+	return validateTag(is.Tag, "tag").
+		Also(is.Builder.Validate(ctx).ViaField("builder")).
+		Also(validateRequired(is.ServiceAccount, "serviceAccount")).
+		Also(is.Source.Validate(ctx).ViaField("source")).
+		Also(validateBuildHistoryLimit(is.FailedBuildHistoryLimit, "failedBuildHistoryLimit")).
+		Also(validateBuildHistoryLimit(is.SuccessBuildHistoryLimit, "successBuildHistoryLimit")).
+		Also(is.validateImageTaggingStrategy()).
+		Also(is.validateCacheSize()).
+		Also(is.validateCacheImage()).
+		Also(is.validateCacheAccessMode()).
+		Also(validateEnv(is.Build.Env).ViaField("build", "env")).
+		Also(validateTimeout(is.Build.Timeout).ViaField("build")).
+		Also(validateBindings(is.Build.Bindings).ViaField("build", "bindings"))
+}
+
+func (ib *ImageBuilder) Validate(ctx context.Context) *apis.FieldError {
+	errs := validateRequired(ib.Name, "name")
+
+	switch ib.Kind {
+	case BuilderKind, ClusterBuilderKind:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(ib.Kind, "kind"))
+	}
+	return errs
+}
+
+func (is *ImageSpec) validateImageTaggingStrategy() *apis.FieldError {
+	switch is.ImageTaggingStrategy {
+	case None, BuildNumber:
+		return nil
+	default:
+		return apis.ErrInvalidValue(string(is.ImageTaggingStrategy), "imageTaggingStrategy")
+	}
+}
+
+func (is *ImageSpec) validateCacheSize() *apis.FieldError {
+	if is.CacheSize != nil && is.CacheSize.Sign() <= 0 {
+		return apis.ErrInvalidValue(is.CacheSize.String(), "cacheSize")
+	}
+	return nil
+}
+
+func (is *ImageSpec) validateCacheImage() *apis.FieldError {
+	if is.CacheImage == "" {
+		return nil
+	}
+
+	if is.CacheSize != nil {
+		return apis.ErrMultipleOneOf("cacheSize", "cacheImage")
+	}
+	return validateTag(is.CacheImage, "cacheImage")
+}
+
+func (is *ImageSpec) validateCacheAccessMode() *apis.FieldError {
+	switch is.CacheAccessMode {
+	case "", corev1.ReadWriteOnce, corev1.ReadWriteMany:
+		return nil
+	default:
+		return apis.ErrInvalidValue(string(is.CacheAccessMode), "cacheAccessMode")
+	}
+}
+
+func validateBuildHistoryLimit(limit *int64, field string) *apis.FieldError {
+	if limit != nil && *limit < 0 {
+		return apis.ErrInvalidValue(strconv.FormatInt(*limit, 10), field)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/knative/pkg/apis"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestImageValidation(t *testing.T) {
+	spec.Run(t, "Image Validation", testImageValidation)
+}
+
+func testImageValidation(t *testing.T, when spec.G, it spec.S) {
+	limit := int64(90)
+	image := &Image{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "image-name",
+		},
+		Spec: ImageSpec{
+			Tag: "some/image",
+			Builder: ImageBuilder{
+				TypeMeta: metav1.TypeMeta{
+					Kind: ClusterBuilderKind,
+				},
+				Name: "builder-name",
+			},
+			ServiceAccount: "some/service-account",
+			Source: SourceConfig{
+				Git: &Git{
+					URL:      "http://github.com/repo",
+					Revision: "master",
+				},
+			},
+			FailedBuildHistoryLimit:  &limit,
+			SuccessBuildHistoryLimit: &limit,
+			ImageTaggingStrategy:     BuildNumber,
+		},
+	}
+
+	when("Default", func() {
+		it("does not modify already set fields", func() {
+			oldImage := image.DeepCopy()
+			image.SetDefaults(context.TODO())
+
+			assert.Equal(t, image, oldImage)
+		})
+
+		it("defaults service account, tagging strategy and builder kind", func() {
+			image.Spec.ServiceAccount = ""
+			image.Spec.ImageTaggingStrategy = ""
+			image.Spec.Builder.Kind = ""
+
+			image.SetDefaults(context.TODO())
+
+			assert.Equal(t, "default", image.Spec.ServiceAccount)
+			assert.Equal(t, BuildNumber, image.Spec.ImageTaggingStrategy)
+			assert.Equal(t, BuilderKind, image.Spec.Builder.Kind)
+		})
+	})
+
+	when("Validate", func() {
+		it("returns nil on no validation error", func() {
+			assert.Nil(t, image.Validate(context.TODO()))
+		})
+
+		assertValidationError := func(image *Image, expectedError *apis.FieldError) {
+			t.Helper()
+			err := image.Validate(context.TODO())
+			assert.EqualError(t, err, expectedError.Error())
+		}
+
+		it("missing field tag", func() {
+			image.Spec.Tag = ""
+			assertValidationError(image, apis.ErrMissingField("tag").ViaField("spec"))
+		})
+
+		it("invalid image tag", func() {
+			image.Spec.Tag = "ftp//invalid/tag@@"
+			assertValidationError(image, apis.ErrInvalidValue(image.Spec.Tag, "tag").ViaField("spec"))
+		})
+
+		it("missing builder name", func() {
+			image.Spec.Builder.Name = ""
+			assertValidationError(image, apis.ErrMissingField("name").ViaField("spec", "builder"))
+		})
+
+		it("invalid builder kind", func() {
+			image.Spec.Builder.Kind = "FakeBuilder"
+			assertValidationError(image, apis.ErrInvalidValue("FakeBuilder", "kind").ViaField("spec", "builder"))
+		})
+
+		it("missing source", func() {
+			image.Spec.Source = SourceConfig{}
+			assertValidationError(image, apis.ErrMissingOneOf("git", "blob", "registry").ViaField("spec", "source"))
+		})
+
+		it("multiple sources", func() {
+			image.Spec.Source.Blob = &Blob{
+				URL: "http://blob.com/url",
+			}
+			assertValidationError(image, apis.ErrMultipleOneOf("git", "blob").ViaField("spec", "source"))
+		})
+
+		it("missing git revision", func() {
+			image.Spec.Source.Git.Revision = ""
+			assertValidationError(image, apis.ErrMissingField("revision").ViaField("spec", "source", "git"))
+		})
+
+		it("watch paths without path filtering", func() {
+			image.Spec.Source.Git.WatchPaths = []string{"shared"}
+			assertValidationError(image, apis.ErrDisallowedFields("watchPaths").ViaField("spec", "source", "git"))
+		})
+
+		it("invalid git watch paths", func() {
+			image.Spec.Source.Git.FilterPaths = true
+			image.Spec.Source.Git.WatchPaths = []string{"shared", "../other-repo"}
+			assertValidationError(image, apis.ErrInvalidValue("../other-repo", apis.CurrentField).ViaFieldIndex("watchPaths", 1).ViaField("spec", "source", "git"))
+		})
+
+		it("invalid blob url", func() {
+			image.Spec.Source = SourceConfig{
+				Blob: &Blob{
+					URL: "not-a-url",
+				},
+			}
+			assertValidationError(image, apis.ErrInvalidValue("not-a-url", "url").ViaField("spec", "source", "blob"))
+		})
+
+		it("invalid blob sha256", func() {
+			image.Spec.Source = SourceConfig{
+				Blob: &Blob{
+					URL:    "https://blob.com/app.zip",
+					SHA256: "not-a-sha",
+				},
+			}
+			assertValidationError(image, apis.ErrInvalidValue("not-a-sha", "sha256").ViaField("spec", "source", "blob"))
+		})
+
+		it("git tree hash", func() {
+			image.Spec.Source.Git.TreeHash = "some-tree-hash"
+			assertValidationError(image, apis.ErrDisallowedFields("treeHash").ViaField("spec", "source", "git"))
+		})
+
+		it("blob revision", func() {
+			image.Spec.Source = SourceConfig{
+				Blob: &Blob{
+					URL:      "https://blob.com/app.zip",
+					Revision: "some-etag",
+				},
+			}
+			assertValidationError(image, apis.ErrDisallowedFields("revision").ViaField("spec", "source", "blob"))
+		})
+
+		it("registry digest", func() {
+			image.Spec.Source = SourceConfig{
+				Registry: &Registry{
+					Image:  "some/source-image",
+					Digest: "sha256:0ff9a4a5a4a8e1e7c1c8a5a7b39f0a6c4b3a3b2b0e3a6f7d2c1b0a9f8e7d6c5b",
+				},
+			}
+			assertValidationError(image, apis.ErrDisallowedFields("digest").ViaField("spec", "source", "registry"))
+		})
+
+		it("negative history limits", func() {
+			negative := int64(-1)
+			image.Spec.FailedBuildHistoryLimit = &negative
+			image.Spec.SuccessBuildHistoryLimit = &negative
+
+			assertValidationError(image,
+				apis.ErrInvalidValue("-1", "failedBuildHistoryLimit").
+					Also(apis.ErrInvalidValue("-1", "successBuildHistoryLimit")).
+					ViaField("spec"))
+		})
+
+		it("invalid image tagging strategy", func() {
+			image.Spec.ImageTaggingStrategy = "Daily"
+			assertValidationError(image, apis.ErrInvalidValue("Daily", "imageTaggingStrategy").ViaField("spec"))
+		})
+
+		it("timeout shorter than a second", func() {
+			image.Spec.Build.Timeout = &metav1.Duration{Duration: 0}
+			assertValidationError(image, apis.ErrInvalidValue("0s", "timeout").ViaField("spec", "build"))
+		})
+
+		it("invalid bindings", func() {
+			image.Spec.Build.Bindings = []Binding{
+				{
+					Name:      "apm",
+					SecretRef: corev1.LocalObjectReference{Name: "apm-secret"},
+				},
+				{
+					Name:        "Not_A_Label",
+					MetadataRef: &corev1.LocalObjectReference{},
+					SecretRef:   corev1.LocalObjectReference{Name: "some-secret"},
+				},
+				{
+					Name: "apm",
+				},
+			}
+
+			assertValidationError(image,
+				apis.ErrInvalidValue("Not_A_Label", "name").
+					Also(apis.ErrMissingField("metadataRef.name")).ViaIndex(1).
+					Also(apis.ErrMissingField("secretRef.name").ViaIndex(2)).
+					Also(apis.ErrInvalidValue("apm", "name").ViaIndex(2)).
+					ViaField("spec", "build", "bindings"))
+		})
+
+		it("zero cache size", func() {
+			zero := resource.MustParse("0")
+			image.Spec.CacheSize = &zero
+			assertValidationError(image, apis.ErrInvalidValue("0", "cacheSize").ViaField("spec"))
+		})
+
+		it("invalid cache image", func() {
+			image.Spec.CacheImage = "not a tag"
+			assertValidationError(image, apis.ErrInvalidValue("not a tag", "cacheImage").ViaField("spec"))
+		})
+
+		it("invalid cache access mode", func() {
+			image.Spec.CacheAccessMode = corev1.ReadOnlyMany
+			assertValidationError(image, apis.ErrInvalidValue("ReadOnlyMany", "cacheAccessMode").ViaField("spec"))
+		})
+
+		it("cache size and cache image", func() {
+			size := resource.MustParse("1G")
+			image.Spec.CacheSize = &size
+			image.Spec.CacheImage = "some/image:cache"
+			assertValidationError(image, apis.ErrMultipleOneOf("cacheSize", "cacheImage").ViaField("spec"))
+		})
+	})
+}
@@ -5,7 +5,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-const ActivePolling = "ActivePolling"
+const (
+	ActivePolling = "ActivePolling"
+
+	ConditionSourceResolved duckv1alpha1.ConditionType = "SourceResolved"
+	SourceResolutionFailed                             = "ResolutionFailed"
+)
 
 func (sr *SourceResolver) ResolvedSource(config ResolvedSourceConfig) {
 	resolvedSource := config.ResolvedSource()
@@ -25,10 +30,53 @@ func (sr *SourceResolver) ResolvedSource(config ResolvedSourceConfig) {
 	if resolvedSource.IsPollable() {
 		pollingStatus = corev1.ConditionTrue
 	}
-	sr.Status.Conditions = append(sr.Status.Conditions, duckv1alpha1.Condition{
-		Type:   ActivePolling,
-		Status: pollingStatus,
-	})
+	sr.Status.Conditions = append(sr.Status.Conditions,
+		duckv1alpha1.Condition{
+			Type:   ActivePolling,
+			Status: pollingStatus,
+		},
+		duckv1alpha1.Condition{
+			Type:   ConditionSourceResolved,
+			Status: corev1.ConditionTrue,
+		},
+	)
+}
+
+// ResolveFailed records why the source could not be resolved. A source resolved for the
+// current generation stays ready so images keep building from the last resolved source.
+func (sr *SourceResolver) ResolveFailed(err error) {
+	sourceResolved := duckv1alpha1.Condition{
+		Type:    ConditionSourceResolved,
+		Status:  corev1.ConditionFalse,
+		Reason:  SourceResolutionFailed,
+		Message: err.Error(),
+	}
+
+	if sr.Status.Source.ResolvedSource() != nil && sr.Status.ObservedGeneration == sr.ObjectMeta.Generation {
+		var conditions duckv1alpha1.Conditions
+		for _, condition := range sr.Status.Conditions {
+			if condition.Type != ConditionSourceResolved {
+				conditions = append(conditions, condition)
+			}
+		}
+		sr.Status.Conditions = append(conditions, sourceResolved)
+		return
+	}
+
+	sr.Status.Source = ResolvedSourceConfig{}
+	sr.Status.Conditions = duckv1alpha1.Conditions{
+		{
+			Type:    duckv1alpha1.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  SourceResolutionFailed,
+			Message: err.Error(),
+		},
+		sourceResolved,
+	}
+}
+
+func (sr *SourceResolver) ResolutionFailed() bool {
+	return sr.Status.GetCondition(ConditionSourceResolved).IsFalse()
 }
 
 func (sr *SourceResolver) ConfigChanged(lastBuild *Build) bool {
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"github.com/knative/pkg/apis"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -16,6 +17,11 @@ type SourceResolver struct {
 	Status            SourceResolverStatus `json:"status"`
 }
 
+var (
+	_ apis.Validatable = (*SourceResolver)(nil)
+	_ apis.Defaultable = (*SourceResolver)(nil)
+)
+
 type SourceResolverSpec struct {
 	ServiceAccount string       `json:"serviceAccount"`
 	Source         SourceConfig `json:"source"`
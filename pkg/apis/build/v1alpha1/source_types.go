@@ -1,6 +1,9 @@
 package v1alpha1
 
 import (
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 )
@@ -31,6 +34,11 @@ type Source interface {
 type Git struct {
 	URL      string `json:"url"`
 	Revision string `json:"revision"`
+	// FilterPaths only reports a new revision when the subPath or one of the WatchPaths changes
+	FilterPaths bool     `json:"filterPaths,omitempty"`
+	WatchPaths  []string `json:"watchPaths,omitempty"`
+	// TreeHash identifies the content of the filtered paths at Revision. Only builds may set it.
+	TreeHash string `json:"treeHash,omitempty"`
 }
 
 func (g *Git) BuildEnvVars() []corev1.EnvVar {
@@ -58,6 +66,10 @@ func (in *Git) ImagePullSecretsVolume() corev1.Volume {
 
 type Blob struct {
 	URL string `json:"url"`
+	// SHA256 is the expected checksum of the blob. It is verified before the blob is extracted.
+	SHA256 string `json:"sha256,omitempty"`
+	// Revision is the Digest, ETag or Last-Modified header of the blob that was built. Only builds may set it.
+	Revision string `json:"revision,omitempty"`
 }
 
 func (b *Blob) ImagePullSecretsVolume() corev1.Volume {
@@ -70,18 +82,28 @@ func (b *Blob) ImagePullSecretsVolume() corev1.Volume {
 }
 
 func (b *Blob) BuildEnvVars() []corev1.EnvVar {
-	return []corev1.EnvVar{
+	envVars := []corev1.EnvVar{
 		{
 			Name:  "BLOB_URL",
 			Value: b.URL,
 		},
-		homeEnv,
 	}
+
+	if b.SHA256 != "" {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "BLOB_SHA256",
+			Value: b.SHA256,
+		})
+	}
+
+	return append(envVars, homeEnv)
 }
 
 type Registry struct {
 	Image            string                        `json:"image"`
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,15,rep,name=imagePullSecrets"`
+	// Digest pins a build to the image the tag resolved to. Images and source resolvers reject it.
+	Digest string `json:"digest,omitempty"`
 }
 
 func (r *Registry) ImagePullSecretsVolume() corev1.Volume {
@@ -108,12 +130,24 @@ func (r *Registry) BuildEnvVars() []corev1.EnvVar {
 	return []corev1.EnvVar{
 		{
 			Name:  "REGISTRY_IMAGE",
-			Value: r.Image,
+			Value: r.pinnedImage(),
 		},
 		homeEnv,
 	}
 }
 
+func (r *Registry) pinnedImage() string {
+	if r.Digest == "" {
+		return r.Image
+	}
+
+	ref, err := name.ParseReference(r.Image, name.WeakValidation)
+	if err != nil {
+		return r.Image
+	}
+	return ref.Context().Name() + "@" + r.Digest
+}
+
 type ResolvedSourceConfig struct {
 	Git      *ResolvedGitSource      `json:"git,omitempty"`
 	Blob     *ResolvedBlobSource     `json:"blob,omitempty"`
@@ -151,6 +185,7 @@ const (
 type ResolvedGitSource struct {
 	URL      string        `json:"url"`
 	Revision string        `json:"commit"`
+	TreeHash string        `json:"treeHash,omitempty"`
 	SubPath  string        `json:"subPath,omitempty"`
 	Type     GitSourceKind `json:"type"`
 }
@@ -160,6 +195,7 @@ func (gs *ResolvedGitSource) SourceConfig() SourceConfig {
 		Git: &Git{
 			URL:      gs.URL,
 			Revision: gs.Revision,
+			TreeHash: gs.TreeHash,
 		},
 		SubPath: gs.SubPath,
 	}
@@ -187,18 +223,26 @@ func (gs *ResolvedGitSource) RevisionChanged(lastBuild *Build) bool {
 		return true
 	}
 
+	if gs.TreeHash != "" && lastBuild.Spec.Source.Git.TreeHash != "" {
+		return gs.TreeHash != lastBuild.Spec.Source.Git.TreeHash
+	}
+
 	return gs.Revision != lastBuild.Spec.Source.Git.Revision
 }
 
 type ResolvedBlobSource struct {
-	URL     string `json:"url"`
-	SubPath string `json:"subPath,omitempty"`
+	URL      string `json:"url"`
+	SHA256   string `json:"sha256,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	SubPath  string `json:"subPath,omitempty"`
 }
 
 func (bs *ResolvedBlobSource) SourceConfig() SourceConfig {
 	return SourceConfig{
 		Blob: &Blob{
-			URL: bs.URL,
+			URL:      bs.URL,
+			SHA256:   bs.SHA256,
+			Revision: bs.Revision,
 		},
 		SubPath: bs.SubPath,
 	}
@@ -209,7 +253,7 @@ func (bs *ResolvedBlobSource) IsUnknown() bool {
 }
 
 func (bs *ResolvedBlobSource) IsPollable() bool {
-	return false
+	return bs.Revision != ""
 }
 
 func (bs *ResolvedBlobSource) ConfigChanged(lastBuild *Build) bool {
@@ -217,16 +261,22 @@ func (bs *ResolvedBlobSource) ConfigChanged(lastBuild *Build) bool {
 		return true
 	}
 	return bs.URL != lastBuild.Spec.Source.Blob.URL ||
+		bs.SHA256 != lastBuild.Spec.Source.Blob.SHA256 ||
 		bs.SubPath != lastBuild.Spec.Source.SubPath
 }
 
 func (bs *ResolvedBlobSource) RevisionChanged(lastBuild *Build) bool {
-	return false
+	if lastBuild.Spec.Source.Blob == nil {
+		return true
+	}
+
+	return bs.Revision != lastBuild.Spec.Source.Blob.Revision
 }
 
 type ResolvedRegistrySource struct {
 	Image            string                        `json:"image"`
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,15,rep,name=imagePullSecrets"`
+	Digest           string                        `json:"digest,omitempty"`
 	SubPath          string                        `json:"subPath,omitempty"`
 }
 
@@ -235,6 +285,7 @@ func (rs *ResolvedRegistrySource) SourceConfig() SourceConfig {
 		Registry: &Registry{
 			Image:            rs.Image,
 			ImagePullSecrets: rs.ImagePullSecrets,
+			Digest:           rs.Digest,
 		},
 		SubPath: rs.SubPath,
 	}
@@ -244,8 +295,9 @@ func (rs *ResolvedRegistrySource) IsUnknown() bool {
 	return false
 }
 
+// IsPollable reports whether the image is referenced by a tag that can be moved to a new digest.
 func (rs *ResolvedRegistrySource) IsPollable() bool {
-	return false
+	return rs.Digest != "" && !strings.Contains(rs.Image, "@")
 }
 
 func (rs *ResolvedRegistrySource) ConfigChanged(lastBuild *Build) bool {
@@ -259,5 +311,9 @@ func (rs *ResolvedRegistrySource) ConfigChanged(lastBuild *Build) bool {
 }
 
 func (rs *ResolvedRegistrySource) RevisionChanged(lastBuild *Build) bool {
-	return false
+	if lastBuild.Spec.Source.Registry == nil {
+		return true
+	}
+
+	return rs.Digest != lastBuild.Spec.Source.Registry.Digest
 }
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2019 The original author or authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+)
+
+var sha256Regex = regexp.MustCompile("^[a-fA-F0-9]{64}$")
+
+type resolvedSourceKey struct{}
+
+// withinResolvedSource marks the source being validated as resolved by kpack. Only resolved sources, such as
+// the source of a build, may set the revision of the source.
+func withinResolvedSource(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resolvedSourceKey{}, true)
+}
+
+func isResolvedSource(ctx context.Context) bool {
+	return ctx.Value(resolvedSourceKey{}) != nil
+}
+
+func (sc *SourceConfig) Validate(ctx context.Context) *apis.FieldError {
+	var sources []string
+	if sc.Git != nil {
+		sources = append(sources, "git")
+	}
+	if sc.Blob != nil {
+		sources = append(sources, "blob")
+	}
+	if sc.Registry != nil {
+		sources = append(sources, "registry")
+	}
+
+	switch len(sources) {
+	case 0:
+		return apis.ErrMissingOneOf("git", "blob", "registry")
+	case 1:
+	default:
+		return apis.ErrMultipleOneOf(sources...)
+	}
+
+	switch {
+	case sc.Git != nil:
+		return sc.Git.Validate(ctx).ViaField("git")
+	case sc.Blob != nil:
+		return sc.Blob.Validate(ctx).ViaField("blob")
+	default:
+		return sc.Registry.Validate(ctx).ViaField("registry")
+	}
+}
+
+func (g *Git) Validate(ctx context.Context) *apis.FieldError {
+	errs := validateRequired(g.URL, "url").
+		Also(validateRequired(g.Revision, "revision"))
+
+	if len(g.WatchPaths) > 0 && !g.FilterPaths {
+		errs = errs.Also(apis.ErrDisallowedFields("watchPaths"))
+	}
+
+	for i, path := range g.WatchPaths {
+		if path == "" || strings.HasPrefix(path, "/") || strings.Contains(path, "..") {
+			errs = errs.Also(apis.ErrInvalidValue(path, apis.CurrentField).ViaFieldIndex("watchPaths", i))
+		}
+	}
+
+	if g.TreeHash != "" && !isResolvedSource(ctx) {
+		errs = errs.Also(apis.ErrDisallowedFields("treeHash"))
+	}
+	return errs
+}
+
+func (b *Blob) Validate(ctx context.Context) *apis.FieldError {
+	if b.URL == "" {
+		return apis.ErrMissingField("url")
+	}
+
+	if u, err := url.Parse(b.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		return apis.ErrInvalidValue(b.URL, "url")
+	}
+
+	if b.SHA256 != "" && !sha256Regex.MatchString(b.SHA256) {
+		return apis.ErrInvalidValue(b.SHA256, "sha256")
+	}
+
+	if b.Revision != "" && !isResolvedSource(ctx) {
+		return apis.ErrDisallowedFields("revision")
+	}
+	return nil
+}
+
+func (r *Registry) Validate(ctx context.Context) *apis.FieldError {
+	errs := validateImage(r.Image, "image")
+
+	if r.Digest != "" && !isResolvedSource(ctx) {
+		errs = errs.Also(apis.ErrDisallowedFields("digest"))
+	}
+	return errs
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2019 The original author or authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func validateRequired(value, field string) *apis.FieldError {
+	if value == "" {
+		return apis.ErrMissingField(field)
+	}
+	return nil
+}
+
+func validateTag(value, field string) *apis.FieldError {
+	if value == "" {
+		return apis.ErrMissingField(field)
+	}
+
+	if _, err := name.NewTag(value, name.WeakValidation); err != nil {
+		return apis.ErrInvalidValue(value, field)
+	}
+	return nil
+}
+
+func validateImage(value, field string) *apis.FieldError {
+	if value == "" {
+		return apis.ErrMissingField(field)
+	}
+
+	if _, err := name.ParseReference(value, name.WeakValidation); err != nil {
+		return apis.ErrInvalidValue(value, field)
+	}
+	return nil
+}
+
+func validateBuilderImage(value string) *apis.FieldError {
+	return validateImage(value, "image")
+}
+
+func validateEnv(env []corev1.EnvVar) *apis.FieldError {
+	var errs *apis.FieldError
+	for i, envVar := range env {
+		if envVar.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("name").ViaIndex(i))
+		}
+	}
+	return errs
+}
+
+func validateTimeout(timeout *metav1.Duration) *apis.FieldError {
+	if timeout != nil && timeout.Duration < time.Second {
+		return apis.ErrInvalidValue(timeout.Duration.String(), "timeout")
+	}
+	return nil
+}
+
+func validateBindings(bindings []Binding) *apis.FieldError {
+	var errs *apis.FieldError
+	names := map[string]bool{}
+	for i, binding := range bindings {
+		errs = errs.Also(binding.validate().ViaIndex(i))
+
+		if names[binding.Name] {
+			errs = errs.Also(apis.ErrInvalidValue(binding.Name, "name").ViaIndex(i))
+		}
+		names[binding.Name] = true
+	}
+	return errs
+}
+
+func (b *Binding) validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if b.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("name"))
+	} else if len(validation.IsDNS1123Label(b.Name)) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(b.Name, "name"))
+	}
+
+	if b.MetadataRef != nil {
+		errs = errs.Also(validateRequired(b.MetadataRef.Name, "metadataRef.name"))
+	}
+
+	return errs.Also(validateRequired(b.SecretRef.Name, "secretRef.name"))
+}
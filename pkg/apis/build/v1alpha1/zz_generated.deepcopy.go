@@ -27,6 +27,28 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Binding) DeepCopyInto(out *Binding) {
+	*out = *in
+	if in.MetadataRef != nil {
+		in, out := &in.MetadataRef, &out.MetadataRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	out.SecretRef = in.SecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Binding.
+func (in *Binding) DeepCopy() *Binding {
+	if in == nil {
+		return nil
+	}
+	out := new(Binding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Blob) DeepCopyInto(out *Blob) {
 	*out = *in
@@ -138,6 +160,47 @@ func (in *BuildSpec) DeepCopyInto(out *BuildSpec) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]Binding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastBuild != nil {
+		in, out := &in.LastBuild, &out.LastBuild
+		*out = new(LastBuild)
+		**out = **in
+	}
 	return
 }
 
@@ -151,6 +214,22 @@ func (in *BuildSpec) DeepCopy() *BuildSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildStack) DeepCopyInto(out *BuildStack) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildStack.
+func (in *BuildStack) DeepCopy() *BuildStack {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildStack)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildStatus) DeepCopyInto(out *BuildStatus) {
 	*out = *in
@@ -172,6 +251,7 @@ func (in *BuildStatus) DeepCopyInto(out *BuildStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	out.Stack = in.Stack
 	return
 }
 
@@ -275,6 +355,43 @@ func (in *BuilderList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuilderOrderBuildpack) DeepCopyInto(out *BuilderOrderBuildpack) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuilderOrderBuildpack.
+func (in *BuilderOrderBuildpack) DeepCopy() *BuilderOrderBuildpack {
+	if in == nil {
+		return nil
+	}
+	out := new(BuilderOrderBuildpack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuilderOrderGroup) DeepCopyInto(out *BuilderOrderGroup) {
+	*out = *in
+	if in.Group != nil {
+		in, out := &in.Group, &out.Group
+		*out = make([]BuilderOrderBuildpack, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuilderOrderGroup.
+func (in *BuilderOrderGroup) DeepCopy() *BuilderOrderGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(BuilderOrderGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuilderSpec) DeepCopyInto(out *BuilderSpec) {
 	*out = *in
@@ -291,6 +408,27 @@ func (in *BuilderSpec) DeepCopy() *BuilderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuilderStackStatus) DeepCopyInto(out *BuilderStackStatus) {
+	*out = *in
+	if in.RunImageMirrors != nil {
+		in, out := &in.RunImageMirrors, &out.RunImageMirrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuilderStackStatus.
+func (in *BuilderStackStatus) DeepCopy() *BuilderStackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BuilderStackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuilderStatus) DeepCopyInto(out *BuilderStatus) {
 	*out = *in
@@ -300,6 +438,14 @@ func (in *BuilderStatus) DeepCopyInto(out *BuilderStatus) {
 		*out = make(BuildpackMetadataList, len(*in))
 		copy(*out, *in)
 	}
+	in.Stack.DeepCopyInto(&out.Stack)
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = make([]BuilderOrderGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -443,6 +589,11 @@ func (in *ClusterBuilderList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Git) DeepCopyInto(out *Git) {
 	*out = *in
+	if in.WatchPaths != nil {
+		in, out := &in.WatchPaths, &out.WatchPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -495,6 +646,42 @@ func (in *ImageBuild) DeepCopyInto(out *ImageBuild) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]Binding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -609,6 +796,22 @@ func (in *ImageStatus) DeepCopy() *ImageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LastBuild) DeepCopyInto(out *LastBuild) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LastBuild.
+func (in *LastBuild) DeepCopy() *LastBuild {
+	if in == nil {
+		return nil
+	}
+	out := new(LastBuild)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReconciledBuild) DeepCopyInto(out *ReconciledBuild) {
 	*out = *in
@@ -748,7 +951,7 @@ func (in *SourceConfig) DeepCopyInto(out *SourceConfig) {
 	if in.Git != nil {
 		in, out := &in.Git, &out.Git
 		*out = new(Git)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Blob != nil {
 		in, out := &in.Blob, &out.Blob
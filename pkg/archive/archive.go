@@ -0,0 +1,322 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type Format string
+
+const (
+	Zip   Format = "zip"
+	Tar   Format = "tar"
+	TarGz Format = "tar.gz"
+)
+
+var contentTypes = map[string]Format{
+	"application/zip":              Zip,
+	"application/x-zip-compressed": Zip,
+	"application/java-archive":     Zip,
+	"application/x-tar":            Tar,
+	"application/gzip":             TarGz,
+	"application/x-gzip":           TarGz,
+	"application/x-gtar":           TarGz,
+	"application/x-compressed-tar": TarGz,
+}
+
+var extensions = []struct {
+	suffix string
+	format Format
+}{
+	{".zip", Zip},
+	{".jar", Zip},
+	{".war", Zip},
+	{".tar.gz", TarGz},
+	{".tgz", TarGz},
+	{".tar", Tar},
+}
+
+// DetectFormat determines the archive format from the content type, then the file extension
+// of the name, and finally the leading bytes of the archive.
+func DetectFormat(contentType, name string, header []byte) (Format, error) {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if format, ok := contentTypes[mediaType]; ok {
+			return format, nil
+		}
+	}
+
+	lowerName := strings.ToLower(path.Base(name))
+	for _, extension := range extensions {
+		if strings.HasSuffix(lowerName, extension.suffix) {
+			return extension.format, nil
+		}
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		return Zip, nil
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		return TarGz, nil
+	case len(header) >= 262 && bytes.Equal(header[257:262], []byte("ustar")):
+		return Tar, nil
+	}
+
+	return "", fmt.Errorf("unable to determine archive format of %s, supported formats are zip, tar and tar.gz", name)
+}
+
+// Extract extracts the archive file into dir. Entries that would be written outside of dir, either by
+// their name or through symlinks extracted earlier, are rejected.
+func Extract(file *os.File, format Format, dir string) error {
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case Zip:
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		return extractZip(file, info.Size(), dir)
+	case Tar:
+		return extractTar(file, dir)
+	case TarGz:
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return errors.Wrap(err, "invalid tar.gz archive")
+		}
+		defer gzipReader.Close()
+		return extractTar(gzipReader, dir)
+	default:
+		return fmt.Errorf("unsupported archive format %s", format)
+	}
+}
+
+func extractZip(reader io.ReaderAt, size int64, dir string) error {
+	zipReader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return errors.Wrap(err, "invalid zip archive")
+	}
+
+	for _, file := range zipReader.File {
+		filePath, err := safeEntryPath(dir, file.Name)
+		if err != nil {
+			return err
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(filePath, os.ModePerm); err != nil {
+				return err
+			}
+			continue
+		}
+
+		srcFile, err := file.Open()
+		if err != nil {
+			return errors.Wrapf(err, "invalid zip entry %s", file.Name)
+		}
+
+		err = writeFile(filePath, file.Mode(), srcFile)
+		srcFile.Close()
+		if err != nil {
+			return errors.Wrapf(err, "unable to extract %s", file.Name)
+		}
+	}
+	return nil
+}
+
+func extractTar(reader io.Reader, dir string) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "invalid tar archive")
+		}
+
+		filePath, err := safeEntryPath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(filePath, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := writeFile(filePath, header.FileInfo().Mode(), tarReader); err != nil {
+				return errors.Wrapf(err, "unable to extract %s", header.Name)
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+				return err
+			}
+			parent, err := filepath.EvalSymlinks(filepath.Dir(filePath))
+			if err != nil {
+				return err
+			}
+			if target, ok := resolveLinkTarget(parent, header.Linkname); path.IsAbs(header.Linkname) || !ok || !within(dir, target) {
+				return fmt.Errorf("symlink %s points outside of the archive", header.Name)
+			}
+			if err := os.Symlink(header.Linkname, filePath); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			targetPath, err := safeEntryPath(dir, header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+				return err
+			}
+			if err := os.Link(targetPath, filePath); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(filePath string, mode os.FileMode, reader io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return err
+	}
+
+	outFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	_, err = io.Copy(outFile, reader)
+	return err
+}
+
+// safeEntryPath joins the archive entry name onto dir, rejecting names that escape dir either lexically or
+// through a symlink in their parent directories. Entries replacing an existing symlink are rejected so that
+// they are not written through it.
+func safeEntryPath(dir, name string) (string, error) {
+	filePath := filepath.Join(dir, name)
+	if !within(dir, filePath) {
+		return "", fmt.Errorf("archive entry %s is outside of the destination directory", name)
+	}
+
+	parent, err := resolvedParent(filePath)
+	if err != nil {
+		return "", err
+	}
+	if !within(dir, parent) {
+		return "", fmt.Errorf("archive entry %s is outside of the destination directory", name)
+	}
+
+	if info, err := os.Lstat(filePath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return "", fmt.Errorf("archive entry %s replaces a symlink", name)
+	}
+	return filePath, nil
+}
+
+// resolvedParent returns the parent directory of filePath with symlinks resolved. Parent directories that do not
+// exist yet are created as regular directories, so only the deepest existing one needs to be resolved.
+func resolvedParent(filePath string) (string, error) {
+	existing := filepath.Dir(filePath)
+	for {
+		_, err := os.Lstat(existing)
+		if err == nil {
+			break
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		existing = filepath.Dir(existing)
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", err
+	}
+
+	remainder, err := filepath.Rel(existing, filepath.Dir(filePath))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolved, remainder), nil
+}
+
+// resolveLinkTarget resolves linkname relative to parent the way the kernel does, following the symlinks
+// extracted so far before applying each "..". A component that does not exist yet may still be extracted
+// as a symlink, so a ".." after one cannot be resolved and is rejected.
+func resolveLinkTarget(parent, linkname string) (string, bool) {
+	const maxLinks = 255
+
+	resolved := parent
+	pending := strings.Split(linkname, "/")
+	exists := true
+	links := 0
+
+	for len(pending) > 0 {
+		component := pending[0]
+		pending = pending[1:]
+
+		switch component {
+		case "", ".":
+			continue
+		case "..":
+			if !exists {
+				return "", false
+			}
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, component)
+		if !exists {
+			resolved = next
+			continue
+		}
+
+		info, err := os.Lstat(next)
+		if os.IsNotExist(err) {
+			exists = false
+			resolved = next
+			continue
+		} else if err != nil {
+			return "", false
+		}
+
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxLinks {
+			return "", false
+		}
+
+		target, err := os.Readlink(next)
+		if err != nil || filepath.IsAbs(target) {
+			return "", false
+		}
+		pending = append(strings.Split(target, "/"), pending...)
+	}
+	return resolved, true
+}
+
+func within(dir, filePath string) bool {
+	filePath = filepath.Clean(filePath)
+	return filePath == filepath.Clean(dir) || strings.HasPrefix(filePath, filepath.Clean(dir)+string(os.PathSeparator))
+}
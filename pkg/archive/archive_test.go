@@ -0,0 +1,291 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pivotal/kpack/pkg/archive"
+)
+
+func TestArchive(t *testing.T) {
+	spec.Run(t, "Archive", testArchive)
+}
+
+func testArchive(t *testing.T, when spec.G, it spec.S) {
+	var (
+		dir     string
+		tempDir string
+	)
+
+	it.Before(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "archive-test")
+		require.NoError(t, err)
+
+		dir = filepath.Join(tempDir, "workspace")
+		require.NoError(t, os.Mkdir(dir, os.ModePerm))
+	})
+
+	it.After(func() {
+		require.NoError(t, os.RemoveAll(tempDir))
+	})
+
+	writeArchive := func(contents []byte) *os.File {
+		file, err := ioutil.TempFile(tempDir, "archive")
+		require.NoError(t, err)
+
+		_, err = file.Write(contents)
+		require.NoError(t, err)
+
+		_, err = file.Seek(0, 0)
+		require.NoError(t, err)
+		return file
+	}
+
+	assertExtracted := func() {
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "some-dir", "some-file"))
+		require.NoError(t, err)
+		assert.Equal(t, "some-contents", string(contents))
+	}
+
+	when("#DetectFormat", func() {
+		it("uses the content type", func() {
+			format, err := archive.DetectFormat("application/gzip", "/app-latest.zip", nil)
+			require.NoError(t, err)
+			assert.Equal(t, archive.TarGz, format)
+		})
+
+		it("uses the file extension when the content type is generic", func() {
+			for name, expected := range map[string]archive.Format{
+				"/app.zip":    archive.Zip,
+				"/app.jar":    archive.Zip,
+				"/app.tar":    archive.Tar,
+				"/app.tar.gz": archive.TarGz,
+				"/app.TGZ":    archive.TarGz,
+			} {
+				format, err := archive.DetectFormat("application/octet-stream", name, nil)
+				require.NoError(t, err)
+				assert.Equal(t, expected, format, name)
+			}
+		})
+
+		it("detects the format from the contents", func() {
+			format, err := archive.DetectFormat("", "/app", zipArchive(t, "some-file"))
+			require.NoError(t, err)
+			assert.Equal(t, archive.Zip, format)
+
+			format, err = archive.DetectFormat("", "/app", tarArchive(t, "some-file"))
+			require.NoError(t, err)
+			assert.Equal(t, archive.Tar, format)
+
+			format, err = archive.DetectFormat("", "/app", gzipped(t, tarArchive(t, "some-file")))
+			require.NoError(t, err)
+			assert.Equal(t, archive.TarGz, format)
+		})
+
+		it("returns an error for unknown formats", func() {
+			_, err := archive.DetectFormat("text/plain", "/app.txt", []byte("some-text"))
+			require.EqualError(t, err, "unable to determine archive format of /app.txt, supported formats are zip, tar and tar.gz")
+		})
+	})
+
+	when("#Extract", func() {
+		it("extracts zip archives", func() {
+			err := archive.Extract(writeArchive(zipArchive(t, "some-dir/some-file")), archive.Zip, dir)
+			require.NoError(t, err)
+
+			assertExtracted()
+		})
+
+		it("extracts tar archives", func() {
+			err := archive.Extract(writeArchive(tarArchive(t, "some-dir/some-file")), archive.Tar, dir)
+			require.NoError(t, err)
+
+			assertExtracted()
+		})
+
+		it("extracts tar.gz archives", func() {
+			err := archive.Extract(writeArchive(gzipped(t, tarArchive(t, "some-dir/some-file"))), archive.TarGz, dir)
+			require.NoError(t, err)
+
+			assertExtracted()
+		})
+
+		it("rejects zip entries outside of the directory", func() {
+			err := archive.Extract(writeArchive(zipArchive(t, "../escaped-file")), archive.Zip, dir)
+			require.EqualError(t, err, "archive entry ../escaped-file is outside of the destination directory")
+
+			_, err = os.Stat(filepath.Join(tempDir, "escaped-file"))
+			assert.True(t, os.IsNotExist(err))
+		})
+
+		it("rejects tar entries outside of the directory", func() {
+			err := archive.Extract(writeArchive(tarArchive(t, "some-dir/../../escaped-file")), archive.Tar, dir)
+			require.EqualError(t, err, "archive entry some-dir/../../escaped-file is outside of the destination directory")
+
+			_, err = os.Stat(filepath.Join(tempDir, "escaped-file"))
+			assert.True(t, os.IsNotExist(err))
+		})
+
+		it("rejects tar symlinks outside of the directory", func() {
+			buf := &bytes.Buffer{}
+			tarWriter := tar.NewWriter(buf)
+			require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+				Name:     "some-link",
+				Typeflag: tar.TypeSymlink,
+				Linkname: "../..",
+			}))
+			require.NoError(t, tarWriter.Close())
+
+			err := archive.Extract(writeArchive(buf.Bytes()), archive.Tar, dir)
+			require.EqualError(t, err, "symlink some-link points outside of the archive")
+		})
+
+		it("extracts symlinks within the directory", func() {
+			buf := &bytes.Buffer{}
+			tarWriter := tar.NewWriter(buf)
+			require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+				Name:     "some-dir/some-link",
+				Typeflag: tar.TypeSymlink,
+				Linkname: "../other-dir",
+			}))
+			require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+				Name:     "other-dir/some-file",
+				Typeflag: tar.TypeReg,
+				Mode:     0644,
+				Size:     int64(len("some-contents")),
+			}))
+			_, err := tarWriter.Write([]byte("some-contents"))
+			require.NoError(t, err)
+			require.NoError(t, tarWriter.Close())
+
+			err = archive.Extract(writeArchive(buf.Bytes()), archive.Tar, dir)
+			require.NoError(t, err)
+
+			contents, err := ioutil.ReadFile(filepath.Join(dir, "some-dir", "some-link", "some-file"))
+			require.NoError(t, err)
+			assert.Equal(t, "some-contents", string(contents))
+		})
+
+		it("rejects tar entries that escape the directory through chained symlinks", func() {
+			buf := &bytes.Buffer{}
+			tarWriter := tar.NewWriter(buf)
+			require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+				Name:     "p/q/a",
+				Typeflag: tar.TypeSymlink,
+				Linkname: "../..",
+			}))
+			require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+				Name:     "p/q/a/l",
+				Typeflag: tar.TypeSymlink,
+				Linkname: "..",
+			}))
+			require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+				Name:     "p/q/a/l/evil",
+				Typeflag: tar.TypeReg,
+				Mode:     0644,
+				Size:     int64(len("some-contents")),
+			}))
+			_, err := tarWriter.Write([]byte("some-contents"))
+			require.NoError(t, err)
+			require.NoError(t, tarWriter.Close())
+
+			err = archive.Extract(writeArchive(buf.Bytes()), archive.Tar, dir)
+			require.EqualError(t, err, "symlink p/q/a/l points outside of the archive")
+
+			_, err = os.Stat(filepath.Join(tempDir, "evil"))
+			assert.True(t, os.IsNotExist(err))
+		})
+
+		it("rejects tar symlinks that escape the directory through an extracted symlink", func() {
+			buf := &bytes.Buffer{}
+			tarWriter := tar.NewWriter(buf)
+			require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+				Name:     "b",
+				Typeflag: tar.TypeSymlink,
+				Linkname: ".",
+			}))
+			require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+				Name:     "a",
+				Typeflag: tar.TypeSymlink,
+				Linkname: "b/../etc",
+			}))
+			require.NoError(t, tarWriter.Close())
+
+			err := archive.Extract(writeArchive(buf.Bytes()), archive.Tar, dir)
+			require.EqualError(t, err, "symlink a points outside of the archive")
+
+			_, err = os.Lstat(filepath.Join(dir, "a"))
+			assert.True(t, os.IsNotExist(err))
+		})
+
+		it("rejects tar entries whose parent is a symlink outside of the directory", func() {
+			require.NoError(t, os.Symlink(tempDir, filepath.Join(dir, "some-link")))
+
+			err := archive.Extract(writeArchive(tarArchive(t, "some-link/escaped-file")), archive.Tar, dir)
+			require.EqualError(t, err, "archive entry some-link/escaped-file is outside of the destination directory")
+
+			_, err = os.Stat(filepath.Join(tempDir, "escaped-file"))
+			assert.True(t, os.IsNotExist(err))
+		})
+
+		it("returns an error for invalid archives", func() {
+			err := archive.Extract(writeArchive([]byte("not-a-zip")), archive.Zip, dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid zip archive")
+		})
+	})
+}
+
+func zipArchive(t *testing.T, name string) []byte {
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+
+	writer, err := zipWriter.Create(name)
+	require.NoError(t, err)
+
+	_, err = writer.Write([]byte("some-contents"))
+	require.NoError(t, err)
+
+	require.NoError(t, zipWriter.Close())
+	return buf.Bytes()
+}
+
+func tarArchive(t *testing.T, name string) []byte {
+	buf := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(buf)
+
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+		Name:     name,
+		Typeflag: tar.TypeReg,
+		Mode:     0644,
+		Size:     int64(len("some-contents")),
+	}))
+
+	_, err := tarWriter.Write([]byte("some-contents"))
+	require.NoError(t, err)
+
+	require.NoError(t, tarWriter.Close())
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, contents []byte) []byte {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+
+	_, err := gzipWriter.Write(contents)
+	require.NoError(t, err)
+
+	require.NoError(t, gzipWriter.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,101 @@
+package blob
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sclient "k8s.io/client-go/kubernetes"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pivotal/kpack/pkg/secret"
+)
+
+const (
+	// TokenKey holds a bearer token sent in the Authorization header
+	TokenKey = "token"
+	// HeaderKeyPrefix prefixes secret keys that are sent as request headers, e.g. header-X-Api-Key
+	HeaderKeyPrefix = "header-"
+)
+
+type Keychain interface {
+	Resolve(namespace, serviceAccount, blobURL string) (http.Header, error)
+}
+
+type k8sBlobKeychain struct {
+	secretManager secret.SecretManager
+}
+
+func NewK8sBlobKeychain(k8sClient k8sclient.Interface) Keychain {
+	return &k8sBlobKeychain{secretManager: secret.SecretManager{
+		Client:        k8sClient,
+		AnnotationKey: v1alpha1.BlobSecretAnnotationPrefix,
+		Matcher:       URLMatch,
+	}}
+}
+
+func (k *k8sBlobKeychain) Resolve(namespace, serviceAccount, blobURL string) (http.Header, error) {
+	if serviceAccount == "" {
+		return http.Header{}, nil
+	}
+
+	secret, err := k.secretManager.MatchingSecretForServiceAccount(serviceAccount, namespace, blobURL)
+	if k8serrors.IsNotFound(err) {
+		return http.Header{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return AuthHeaders(secret.Data), nil
+}
+
+// AuthHeaders builds the request headers for a blob secret. Basic auth credentials are used when
+// the secret has a username and password, otherwise the token and header- keys are used.
+func AuthHeaders(data map[string][]byte) http.Header {
+	headers := http.Header{}
+
+	username, hasUsername := data[corev1.BasicAuthUsernameKey]
+	password, hasPassword := data[corev1.BasicAuthPasswordKey]
+	if hasUsername && hasPassword {
+		request := &http.Request{Header: headers}
+		request.SetBasicAuth(string(username), string(password))
+		return headers
+	}
+
+	if token, ok := data[TokenKey]; ok {
+		headers.Set("Authorization", fmt.Sprintf("Bearer %s", strings.TrimSpace(string(token))))
+	}
+
+	for key, value := range data {
+		if strings.HasPrefix(key, HeaderKeyPrefix) {
+			headers.Set(strings.TrimPrefix(key, HeaderKeyPrefix), strings.TrimSpace(string(value)))
+		}
+	}
+	return headers
+}
+
+var matchingDomains = []string{
+	// Allow naked domains
+	"%s",
+	// Allow scheme-prefixed.
+	"https://%s",
+	"http://%s",
+}
+
+func URLMatch(blobURL, annotatedURL string) bool {
+	u, err := url.Parse(blobURL)
+	if err != nil {
+		return false
+	}
+
+	for _, format := range matchingDomains {
+		if fmt.Sprintf(format, u.Host) == annotatedURL {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,116 @@
+package blob_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pivotal/kpack/pkg/blob"
+)
+
+func TestBlobAuth(t *testing.T) {
+	spec.Run(t, "Blob Auth", testBlobAuth)
+}
+
+func testBlobAuth(t *testing.T, when spec.G, it spec.S) {
+	when("#AuthHeaders", func() {
+		it("uses basic auth credentials", func() {
+			headers := blob.AuthHeaders(map[string][]byte{
+				"username": []byte("some-username"),
+				"password": []byte("some-password"),
+			})
+
+			assert.Equal(t, "Basic c29tZS11c2VybmFtZTpzb21lLXBhc3N3b3Jk", headers.Get("Authorization"))
+		})
+
+		it("uses a bearer token", func() {
+			headers := blob.AuthHeaders(map[string][]byte{
+				"token": []byte("some-token\n"),
+			})
+
+			assert.Equal(t, "Bearer some-token", headers.Get("Authorization"))
+		})
+
+		it("uses arbitrary headers", func() {
+			headers := blob.AuthHeaders(map[string][]byte{
+				"header-X-Api-Key":   []byte("some-key"),
+				"header-X-Tenant":    []byte("some-tenant"),
+				"some-unrelated-key": []byte("ignored"),
+			})
+
+			assert.Equal(t, http.Header{
+				"X-Api-Key": []string{"some-key"},
+				"X-Tenant":  []string{"some-tenant"},
+			}, headers)
+		})
+	})
+
+	when("#URLMatch", func() {
+		it("matches the host of the blob url", func() {
+			assert.True(t, blob.URLMatch("https://blobs.example.com/some/app.zip", "blobs.example.com"))
+			assert.True(t, blob.URLMatch("https://blobs.example.com/some/app.zip", "https://blobs.example.com"))
+			assert.False(t, blob.URLMatch("https://blobs.example.com/some/app.zip", "other.example.com"))
+		})
+	})
+
+	when("K8sBlobKeychain", func() {
+		const (
+			namespace      = "some-namespace"
+			serviceAccount = "some-service-account"
+		)
+
+		fakeClient := fake.NewSimpleClientset(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "blob-secret",
+					Namespace: namespace,
+					Annotations: map[string]string{
+						v1alpha1.BlobSecretAnnotationPrefix: "https://blobs.example.com",
+					},
+				},
+				Type: corev1.SecretTypeOpaque,
+				Data: map[string][]byte{
+					"token": []byte("some-token"),
+				},
+			},
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      serviceAccount,
+					Namespace: namespace,
+				},
+				Secrets: []corev1.ObjectReference{
+					{Name: "blob-secret"},
+				},
+			},
+		)
+		keychain := blob.NewK8sBlobKeychain(fakeClient)
+
+		it("returns the headers of the matching secret", func() {
+			headers, err := keychain.Resolve(namespace, serviceAccount, "https://blobs.example.com/app.zip")
+			require.NoError(t, err)
+
+			assert.Equal(t, "Bearer some-token", headers.Get("Authorization"))
+		})
+
+		it("returns no headers without a matching secret", func() {
+			headers, err := keychain.Resolve(namespace, serviceAccount, "https://other.example.com/app.zip")
+			require.NoError(t, err)
+
+			assert.Empty(t, headers)
+		})
+
+		it("returns no headers without a service account", func() {
+			headers, err := keychain.Resolve(namespace, "", "https://blobs.example.com/app.zip")
+			require.NoError(t, err)
+
+			assert.Empty(t, headers)
+		})
+	})
+}
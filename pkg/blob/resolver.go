@@ -1,15 +1,38 @@
 package blob
 
-import "github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+)
 
 type Resolver struct {
+	Client   *http.Client
+	Keychain Keychain
 }
 
-func (*Resolver) Resolve(sourceResolver *v1alpha1.SourceResolver) (v1alpha1.ResolvedSourceConfig, error) {
+func (r *Resolver) Resolve(sourceResolver *v1alpha1.SourceResolver) (v1alpha1.ResolvedSourceConfig, error) {
+	headers := http.Header{}
+	if r.Keychain != nil {
+		var err error
+		headers, err = r.Keychain.Resolve(sourceResolver.Namespace, sourceResolver.Spec.ServiceAccount, sourceResolver.Spec.Source.Blob.URL)
+		if err != nil {
+			return v1alpha1.ResolvedSourceConfig{}, err
+		}
+	}
+
+	revision, err := r.revision(sourceResolver.Spec.Source.Blob.URL, headers)
+	if err != nil {
+		return v1alpha1.ResolvedSourceConfig{}, err
+	}
+
 	return v1alpha1.ResolvedSourceConfig{
 		Blob: &v1alpha1.ResolvedBlobSource{
-			URL:     sourceResolver.Spec.Source.Blob.URL,
-			SubPath: sourceResolver.Spec.Source.SubPath,
+			URL:      sourceResolver.Spec.Source.Blob.URL,
+			SHA256:   sourceResolver.Spec.Source.Blob.SHA256,
+			Revision: revision,
+			SubPath:  sourceResolver.Spec.Source.SubPath,
 		},
 	}, nil
 }
@@ -17,3 +40,59 @@ func (*Resolver) Resolve(sourceResolver *v1alpha1.SourceResolver) (v1alpha1.Reso
 func (*Resolver) CanResolve(sourceResolver *v1alpha1.SourceResolver) bool {
 	return sourceResolver.IsBlob()
 }
+
+// revision identifies the current content of the blob from its response headers without downloading it.
+// Blobstores that only accept GET requests, such as presigned urls, are asked for the first byte instead.
+func (r *Resolver) revision(url string, headers http.Header) (string, error) {
+	resp, err := r.request(http.MethodHead, url, headers)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode == http.StatusForbidden ||
+		resp.StatusCode == http.StatusMethodNotAllowed ||
+		resp.StatusCode == http.StatusNotImplemented {
+		resp, err = r.request(http.MethodGet, url, headers)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("unable to fetch blob %s: %s", url, resp.Status)
+	}
+
+	for _, header := range []string{"Digest", "ETag", "Last-Modified"} {
+		if value := resp.Header.Get(header); value != "" {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *Resolver) request(method, url string, headers http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range headers {
+		req.Header[key] = values
+	}
+
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
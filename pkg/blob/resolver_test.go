@@ -0,0 +1,157 @@
+package blob_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pivotal/kpack/pkg/blob"
+)
+
+func TestBlobResolver(t *testing.T) {
+	spec.Run(t, "Blob Resolver", testBlobResolver)
+}
+
+func testBlobResolver(t *testing.T, when spec.G, it spec.S) {
+	var (
+		headers  map[string]string
+		handler  http.HandlerFunc
+		server   *httptest.Server
+		resolver = &blob.Resolver{}
+	)
+
+	it.Before(func() {
+		headers = map[string]string{}
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			for key, value := range headers {
+				w.Header().Set(key, value)
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(w, r)
+		}))
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	sourceResolver := func() *v1alpha1.SourceResolver {
+		return &v1alpha1.SourceResolver{
+			Spec: v1alpha1.SourceResolverSpec{
+				Source: v1alpha1.SourceConfig{
+					Blob: &v1alpha1.Blob{
+						URL: server.URL + "/app-latest.zip",
+					},
+					SubPath: "some-path",
+				},
+			},
+		}
+	}
+
+	when("#Resolve", func() {
+		it("records the etag as the revision", func() {
+			headers["ETag"] = `"some-etag"`
+			headers["Last-Modified"] = "Wed, 15 May 2019 15:20:30 GMT"
+
+			resolvedSource, err := resolver.Resolve(sourceResolver())
+			require.NoError(t, err)
+
+			assert.Equal(t, v1alpha1.ResolvedSourceConfig{
+				Blob: &v1alpha1.ResolvedBlobSource{
+					URL:      server.URL + "/app-latest.zip",
+					Revision: `"some-etag"`,
+					SubPath:  "some-path",
+				},
+			}, resolvedSource)
+			assert.True(t, resolvedSource.Blob.IsPollable())
+		})
+
+		it("prefers the content digest", func() {
+			headers["Digest"] = "sha-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE="
+			headers["ETag"] = `"some-etag"`
+
+			resolvedSource, err := resolver.Resolve(sourceResolver())
+			require.NoError(t, err)
+
+			assert.Equal(t, "sha-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=", resolvedSource.Blob.Revision)
+		})
+
+		it("falls back to the last modified time", func() {
+			headers["Last-Modified"] = "Wed, 15 May 2019 15:20:30 GMT"
+
+			resolvedSource, err := resolver.Resolve(sourceResolver())
+			require.NoError(t, err)
+
+			assert.Equal(t, "Wed, 15 May 2019 15:20:30 GMT", resolvedSource.Blob.Revision)
+		})
+
+		it("does not poll blobs without a revision", func() {
+			resolvedSource, err := resolver.Resolve(sourceResolver())
+			require.NoError(t, err)
+
+			assert.Equal(t, "", resolvedSource.Blob.Revision)
+			assert.False(t, resolvedSource.Blob.IsPollable())
+		})
+
+		it("uses a ranged GET when HEAD requests are not allowed", func() {
+			var methods []string
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				methods = append(methods, r.Method)
+				if r.Method == http.MethodHead {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+
+				assert.Equal(t, "bytes=0-0", r.Header.Get("Range"))
+				w.Header().Set("ETag", `"some-etag"`)
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write([]byte("a"))
+			}
+
+			resolvedSource, err := resolver.Resolve(sourceResolver())
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{http.MethodHead, http.MethodGet}, methods)
+			assert.Equal(t, `"some-etag"`, resolvedSource.Blob.Revision)
+		})
+
+		it("returns an error when the blob cannot be fetched", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}
+
+			_, err := resolver.Resolve(sourceResolver())
+			require.EqualError(t, err, "unable to fetch blob "+server.URL+"/app-latest.zip: 404 Not Found")
+		})
+
+		it("sends the headers from the keychain", func() {
+			var authorization string
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+				w.WriteHeader(http.StatusOK)
+			}
+
+			resolver := &blob.Resolver{Keychain: fakeKeychain{
+				"Authorization": []string{"Bearer some-token"},
+			}}
+
+			_, err := resolver.Resolve(sourceResolver())
+			require.NoError(t, err)
+
+			assert.Equal(t, "Bearer some-token", authorization)
+		})
+	})
+}
+
+type fakeKeychain http.Header
+
+func (f fakeKeychain) Resolve(namespace, serviceAccount, blobURL string) (http.Header, error) {
+	return http.Header(f), nil
+}
@@ -87,7 +87,7 @@ func testGenerator(t *testing.T, when spec.G, it spec.S) {
 				SourceInitImage: "source/init:image",
 				BuildInitImage:  "build/init:image",
 				CredsInitImage:  "creds/init:image",
-				NopImage:        "no/op:image",
+				CompletionImage: "completion/image:image",
 			}
 			generator := &buildpod.Generator{
 				BuildPodConfig: buildPodConfig,
@@ -0,0 +1,34 @@
+package cnb
+
+import (
+	"github.com/BurntSushi/toml"
+)
+
+type buildpackGroup struct {
+	Buildpacks []groupBuildpack `toml:"buildpacks"`
+	Group      []groupBuildpack `toml:"group"`
+}
+
+type groupBuildpack struct {
+	ID      string `toml:"id"`
+	Version string `toml:"version"`
+}
+
+// ReadBuildpackGroup reads the buildpacks selected by the lifecycle detector from its group.toml.
+// Older lifecycles write them as [[buildpacks]] tables and newer ones as [[group]] tables.
+// A missing file is returned as an os.IsNotExist error.
+func ReadBuildpackGroup(path string) ([]BuildpackMetadata, error) {
+	var group buildpackGroup
+	if _, err := toml.DecodeFile(path, &group); err != nil {
+		return nil, err
+	}
+
+	buildpacks := make([]BuildpackMetadata, 0, len(group.Buildpacks)+len(group.Group))
+	for _, bp := range append(group.Buildpacks, group.Group...) {
+		buildpacks = append(buildpacks, BuildpackMetadata{
+			ID:      bp.ID,
+			Version: bp.Version,
+		})
+	}
+	return buildpacks, nil
+}
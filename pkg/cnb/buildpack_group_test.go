@@ -0,0 +1,92 @@
+package cnb_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pivotal/kpack/pkg/cnb"
+)
+
+func TestReadBuildpackGroup(t *testing.T) {
+	spec.Run(t, "Read Buildpack Group", testReadBuildpackGroup)
+}
+
+func testReadBuildpackGroup(t *testing.T, when spec.G, it spec.S) {
+	var (
+		dir  string
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "group")
+		require.NoError(t, err)
+		path = filepath.Join(dir, "group.toml")
+	})
+
+	it.After(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+
+	it("reads the buildpacks selected by the detector", func() {
+		require.NoError(t, ioutil.WriteFile(path, []byte(`
+[[buildpacks]]
+  id = "org.cloudfoundry.node-engine"
+  version = "0.0.49"
+
+[[buildpacks]]
+  id = "org.cloudfoundry.npm"
+  version = "0.0.30"
+  optional = true
+`), 0644))
+
+		buildpacks, err := cnb.ReadBuildpackGroup(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, []cnb.BuildpackMetadata{
+			{ID: "org.cloudfoundry.node-engine", Version: "0.0.49"},
+			{ID: "org.cloudfoundry.npm", Version: "0.0.30"},
+		}, buildpacks)
+	})
+
+	it("reads the group written by newer lifecycles", func() {
+		require.NoError(t, ioutil.WriteFile(path, []byte(`
+[[group]]
+  id = "org.cloudfoundry.node-engine"
+  version = "0.0.49"
+
+[[group]]
+  id = "org.cloudfoundry.npm"
+  version = "0.0.30"
+`), 0644))
+
+		buildpacks, err := cnb.ReadBuildpackGroup(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, []cnb.BuildpackMetadata{
+			{ID: "org.cloudfoundry.node-engine", Version: "0.0.49"},
+			{ID: "org.cloudfoundry.npm", Version: "0.0.30"},
+		}, buildpacks)
+	})
+
+	it("returns a not exist error when the detector did not run", func() {
+		_, err := cnb.ReadBuildpackGroup(path)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	it("returns an error for invalid toml", func() {
+		require.NoError(t, ioutil.WriteFile(path, []byte(`
+[[buildpacks]]
+  id = org.cloudfoundry.npm
+`), 0644))
+
+		_, err := cnb.ReadBuildpackGroup(path)
+		assert.Error(t, err)
+	})
+}
@@ -2,15 +2,22 @@ package cnb
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	lcyclemd "github.com/buildpack/lifecycle/metadata"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
 	"github.com/pivotal/kpack/pkg/registry"
 )
 
-const BuilderMetadataLabel = "io.buildpacks.builder.metadata"
+const (
+	BuilderMetadataLabel = "io.buildpacks.builder.metadata"
+	StackIDLabel         = "io.buildpacks.stack.id"
+
+	appMetadataLabel = "io.buildpacks.lifecycle.metadata"
+)
 
 type BuildpackMetadata struct {
 	ID      string `json:"id"`
@@ -18,12 +25,50 @@ type BuildpackMetadata struct {
 }
 
 type BuilderImageMetadata struct {
-	Buildpacks []BuildpackMetadata `json:"buildpacks"`
+	Description string                 `json:"description"`
+	Stack       BuilderStackMetadata   `json:"stack"`
+	Buildpacks  []BuildpackMetadata    `json:"buildpacks"`
+	Groups      []BuilderGroupMetadata `json:"groups"`
+	Lifecycle   LifecycleMetadata      `json:"lifecycle"`
+}
+
+type BuilderStackMetadata struct {
+	BuildImage struct {
+		Image string `json:"image"`
+	} `json:"buildImage"`
+	RunImage struct {
+		Image   string   `json:"image"`
+		Mirrors []string `json:"mirrors"`
+	} `json:"runImage"`
+}
+
+type BuilderGroupMetadata struct {
+	Buildpacks []BuilderGroupBuildpack `json:"buildpacks"`
+}
+
+type BuilderGroupBuildpack struct {
+	ID       string `json:"id"`
+	Version  string `json:"version"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+type LifecycleMetadata struct {
+	Version string `json:"version"`
 }
 
 type BuilderImage struct {
 	BuilderBuildpackMetadata BuilderMetadata
 	Identifier               string
+	Stack                    BuilderStack
+	LifecycleVersion         string
+	Order                    []BuilderGroupMetadata
+}
+
+type BuilderStack struct {
+	ID              string
+	BuildImage      string
+	RunImage        string
+	RunImageMirrors []string
 }
 
 type BuilderMetadata []BuildpackMetadata
@@ -55,12 +100,55 @@ func (r *RemoteMetadataRetriever) GetBuilderImage(repo registry.ImageRef) (Build
 		return BuilderImage{}, errors.Wrap(err, "failed to retrieve builder image SHA")
 	}
 
+	stackID, err := img.Label(StackIDLabel)
+	if err != nil {
+		return BuilderImage{}, errors.Wrap(err, "builder image stack id label not present")
+	}
+
+	runImage, err := r.resolveRunImage(repo, metadata.Stack.RunImage.Image)
+	if err != nil {
+		return BuilderImage{}, errors.Wrap(err, "unable to fetch remote run image")
+	}
+
 	return BuilderImage{
 		BuilderBuildpackMetadata: metadata.Buildpacks,
 		Identifier:               identifier,
+		Stack: BuilderStack{
+			ID:              stackID,
+			BuildImage:      metadata.Stack.BuildImage.Image,
+			RunImage:        runImage,
+			RunImageMirrors: metadata.Stack.RunImage.Mirrors,
+		},
+		LifecycleVersion: metadata.Lifecycle.Version,
+		Order:            metadata.Groups,
 	}, nil
 }
 
+// resolveRunImage pins the builder's stack run image to its current digest so
+// a change to the run image can be detected without a new builder image.
+func (r *RemoteMetadataRetriever) resolveRunImage(builderRef registry.ImageRef, runImage string) (string, error) {
+	if runImage == "" {
+		return "", nil
+	}
+
+	img, err := r.RemoteImageFactory.NewRemote(&runImageRef{ImageRef: builderRef, image: runImage})
+	if err != nil {
+		return "", err
+	}
+
+	return img.Identifier()
+}
+
+// runImageRef fetches the run image with the credentials of the builder it belongs to.
+type runImageRef struct {
+	registry.ImageRef
+	image string
+}
+
+func (r *runImageRef) Image() string {
+	return r.image
+}
+
 func (r *RemoteMetadataRetriever) GetBuiltImage(ref registry.ImageRef) (BuiltImage, error) {
 	img, err := r.RemoteImageFactory.NewRemote(ref)
 	if err != nil {
@@ -89,15 +177,97 @@ func (r *RemoteMetadataRetriever) GetBuiltImage(ref registry.ImageRef) (BuiltIma
 		return BuiltImage{}, err
 	}
 
+	stack, err := builtImageStack(img)
+	if err != nil {
+		return BuiltImage{}, err
+	}
+
+	buildpackMetadata := make([]BuildpackMetadata, 0, len(metadata.Buildpacks))
+	for _, bp := range metadata.Buildpacks {
+		buildpackMetadata = append(buildpackMetadata, BuildpackMetadata{
+			ID:      bp.ID,
+			Version: bp.Version,
+		})
+	}
+
 	return BuiltImage{
 		Identifier:        identifier,
 		CompletedAt:       imageCreatedAt,
-		BuildpackMetadata: metadata.Buildpacks,
+		BuildpackMetadata: buildpackMetadata,
+		Stack:             stack,
+	}, nil
+}
+
+// GetBuiltImageIdentifier only fetches the manifest of the built image to resolve its digest.
+func (r *RemoteMetadataRetriever) GetBuiltImageIdentifier(ref registry.ImageRef) (string, error) {
+	img, err := r.RemoteImageFactory.NewRemote(ref)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to fetch remote built image")
+	}
+
+	return img.Identifier()
+}
+
+type appImageMetadata struct {
+	RunImage struct {
+		SHA string `json:"sha"`
+	} `json:"runImage"`
+	Stack struct {
+		RunImage struct {
+			Image string `json:"image"`
+		} `json:"runImage"`
+	} `json:"stack"`
+}
+
+func builtImageStack(img registry.RemoteImage) (BuiltImageStack, error) {
+	stackID, err := img.Label(StackIDLabel)
+	if err != nil {
+		return BuiltImageStack{}, err
+	}
+
+	metadataJSON, err := img.Label(appMetadataLabel)
+	if err != nil {
+		return BuiltImageStack{}, err
+	}
+
+	if metadataJSON == "" {
+		return BuiltImageStack{ID: stackID}, nil
+	}
+
+	var metadata appImageMetadata
+	err = json.Unmarshal([]byte(metadataJSON), &metadata)
+	if err != nil {
+		return BuiltImageStack{}, errors.Wrap(err, "unsupported lifecycle metadata structure")
+	}
+
+	return BuiltImageStack{
+		RunImage: runImageReference(metadata.Stack.RunImage.Image, metadata.RunImage.SHA),
+		ID:       stackID,
 	}, nil
 }
 
+// runImageReference pins the stack run image to the digest the app image was built on.
+func runImageReference(runImage, digest string) string {
+	if runImage == "" || digest == "" {
+		return runImage
+	}
+
+	ref, err := name.ParseReference(runImage, name.WeakValidation)
+	if err != nil {
+		return runImage
+	}
+
+	return fmt.Sprintf("%s@%s", ref.Context().Name(), digest)
+}
+
 type BuiltImage struct {
-	Identifier        string
-	CompletedAt       time.Time
-	BuildpackMetadata []lcyclemd.BuildpackMetadata
+	Identifier        string              `json:"identifier"`
+	CompletedAt       time.Time           `json:"completedAt"`
+	BuildpackMetadata []BuildpackMetadata `json:"buildpacks"`
+	Stack             BuiltImageStack     `json:"stack"`
+}
+
+type BuiltImageStack struct {
+	RunImage string `json:"runImage"`
+	ID       string `json:"id"`
 }
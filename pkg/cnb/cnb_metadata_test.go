@@ -42,6 +42,68 @@ func testMetadataRetriever(t *testing.T, when spec.G, it spec.S) {
 
 				assert.Equal(t, "index.docker.io/builder/image@sha256:2bc85afc0ee0aec012b3889cf5f2e9690bb504c9d19ce90add2f415b85990895", builderImage.Identifier)
 			})
+
+			it("resolves the digest of the stack run image", func() {
+				fakeBuilderImage := registryfakes.NewFakeRemoteImage("index.docker.io/builder/image", "sha256:2bc85afc0ee0aec012b3889cf5f2e9690bb504c9d19ce90add2f415b85990895")
+				err := fakeBuilderImage.SetLabel("io.buildpacks.builder.metadata", `{"buildpacks": [{"id": "test.id", "version": "1.2.3"}], "stack": {"runImage": {"image": "cloudfoundry/run:base-cnb"}}}`)
+				require.NoError(t, err)
+
+				fakeRunImage := registryfakes.NewFakeRemoteImage("index.docker.io/cloudfoundry/run", "sha256:1bc7e5e790001c71c2cfb175854dd36e65e0b71c58294b331a519be95bdec4ef")
+
+				mockFactory.NewRemoteReturnsOnCall(0, fakeBuilderImage, nil)
+				mockFactory.NewRemoteReturnsOnCall(1, fakeRunImage, nil)
+
+				imageRef := registry.NewNoAuthImageRef("test-repo-name")
+				subject := cnb.RemoteMetadataRetriever{RemoteImageFactory: mockFactory}
+				builderImage, err := subject.GetBuilderImage(imageRef)
+				require.NoError(t, err)
+
+				assert.Equal(t, "index.docker.io/cloudfoundry/run@sha256:1bc7e5e790001c71c2cfb175854dd36e65e0b71c58294b331a519be95bdec4ef", builderImage.Stack.RunImage)
+
+				require.Equal(t, 2, mockFactory.NewRemoteCallCount())
+				runImageRef := mockFactory.NewRemoteArgsForCall(1)
+				assert.Equal(t, "cloudfoundry/run:base-cnb", runImageRef.Image())
+				assert.Equal(t, imageRef.ServiceAccount(), runImageRef.ServiceAccount())
+				assert.Equal(t, imageRef.Namespace(), runImageRef.Namespace())
+			})
+
+			it("gets the stack, lifecycle and order of the builder", func() {
+				fakeBuilderImage := registryfakes.NewFakeRemoteImage("index.docker.io/builder/image", "sha256:2bc85afc0ee0aec012b3889cf5f2e9690bb504c9d19ce90add2f415b85990895")
+				err := fakeBuilderImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.bionic")
+				require.NoError(t, err)
+				err = fakeBuilderImage.SetLabel("io.buildpacks.builder.metadata", `{
+  "buildpacks": [{"id": "test.id", "version": "1.2.3"}, {"id": "test.optional", "version": "4.5.6"}],
+  "groups": [{"buildpacks": [{"id": "test.id", "version": "1.2.3"}, {"id": "test.optional", "version": "4.5.6", "optional": true}]}],
+  "stack": {"buildImage": {"image": "cloudfoundry/build:base-cnb"}, "runImage": {"image": "cloudfoundry/run:base-cnb", "mirrors": ["gcr.io/cloudfoundry/run:base-cnb"]}},
+  "lifecycle": {"version": "0.3.0"}
+}`)
+				require.NoError(t, err)
+
+				fakeRunImage := registryfakes.NewFakeRemoteImage("index.docker.io/cloudfoundry/run", "sha256:1bc7e5e790001c71c2cfb175854dd36e65e0b71c58294b331a519be95bdec4ef")
+
+				mockFactory.NewRemoteReturnsOnCall(0, fakeBuilderImage, nil)
+				mockFactory.NewRemoteReturnsOnCall(1, fakeRunImage, nil)
+
+				subject := cnb.RemoteMetadataRetriever{RemoteImageFactory: mockFactory}
+				builderImage, err := subject.GetBuilderImage(registry.NewNoAuthImageRef("test-repo-name"))
+				require.NoError(t, err)
+
+				assert.Equal(t, cnb.BuilderStack{
+					ID:              "io.buildpacks.stacks.bionic",
+					BuildImage:      "cloudfoundry/build:base-cnb",
+					RunImage:        "index.docker.io/cloudfoundry/run@sha256:1bc7e5e790001c71c2cfb175854dd36e65e0b71c58294b331a519be95bdec4ef",
+					RunImageMirrors: []string{"gcr.io/cloudfoundry/run:base-cnb"},
+				}, builderImage.Stack)
+				assert.Equal(t, "0.3.0", builderImage.LifecycleVersion)
+				assert.Equal(t, []cnb.BuilderGroupMetadata{
+					{
+						Buildpacks: []cnb.BuilderGroupBuildpack{
+							{ID: "test.id", Version: "1.2.3"},
+							{ID: "test.optional", Version: "4.5.6", Optional: true},
+						},
+					},
+				}, builderImage.Order)
+			})
 		})
 
 		when("GetBuiltImage", func() {
@@ -70,6 +132,28 @@ func testMetadataRetriever(t *testing.T, when spec.G, it spec.S) {
 				assert.Equal(t, result.Identifier, "index.docker.io/built/image@sha256:dc7e5e790001c71c2cfb175854dd36e65e0b71c58294b331a519be95bdec4ef4")
 				assert.Equal(t, mockFactory.NewRemoteArgsForCall(0), fakeImageRef)
 			})
+
+			it("retrieves the stack the image was built on", func() {
+				fakeImage := registryfakes.NewFakeRemoteImage("index.docker.io/built/image", "sha256:dc7e5e790001c71c2cfb175854dd36e65e0b71c58294b331a519be95bdec4ef4")
+				err := fakeImage.SetLabel("io.buildpacks.build.metadata", `{"buildpacks": [{"id": "test.id", "version": "1.2.3"}]}`)
+				require.NoError(t, err)
+				err = fakeImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.bionic")
+				require.NoError(t, err)
+				err = fakeImage.SetLabel("io.buildpacks.lifecycle.metadata", `{"runImage": {"sha": "sha256:1bc7e5e790001c71c2cfb175854dd36e65e0b71c58294b331a519be95bdec4ef"}, "stack": {"runImage": {"image": "cloudfoundry/run:base-cnb"}}}`)
+				require.NoError(t, err)
+
+				mockFactory.NewRemoteReturns(fakeImage, nil)
+
+				subject := cnb.RemoteMetadataRetriever{RemoteImageFactory: mockFactory}
+
+				result, err := subject.GetBuiltImage(registry.NewNoAuthImageRef("built/image:tag"))
+				require.NoError(t, err)
+
+				assert.Equal(t, cnb.BuiltImageStack{
+					RunImage: "index.docker.io/cloudfoundry/run@sha256:1bc7e5e790001c71c2cfb175854dd36e65e0b71c58294b331a519be95bdec4ef",
+					ID:       "io.buildpacks.stacks.bionic",
+				}, result.Stack)
+			})
 		})
 	})
 }
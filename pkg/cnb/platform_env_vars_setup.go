@@ -7,7 +7,11 @@ import (
 	"path"
 )
 
-func SetupPlatformEnvVars(dir, envVarsJSON string) error {
+// PlatformEnvVarPrefix prefixes the env vars that hold the resolved values of
+// build env vars sourced from secrets or config maps.
+const PlatformEnvVarPrefix = "PLATFORM_ENV_"
+
+func SetupPlatformEnvVars(dir, envVarsJSON string, lookupEnv func(string) (string, bool)) error {
 	var envVars []envVariable
 	err := json.Unmarshal([]byte(envVarsJSON), &envVars)
 	if err != nil {
@@ -20,7 +24,17 @@ func SetupPlatformEnvVars(dir, envVarsJSON string) error {
 	}
 
 	for _, envVar := range envVars {
-		err = ioutil.WriteFile(path.Join(folder, envVar.Name), []byte(envVar.Value), os.ModePerm)
+		value := envVar.Value
+		if envVar.ValueFrom != nil {
+			var ok bool
+			value, ok = lookupEnv(PlatformEnvVarPrefix + envVar.Name)
+			if !ok {
+				// optional references that could not be resolved are left out
+				continue
+			}
+		}
+
+		err = ioutil.WriteFile(path.Join(folder, envVar.Name), []byte(value), os.ModePerm)
 		if err != nil {
 			return err
 		}
@@ -29,6 +43,7 @@ func SetupPlatformEnvVars(dir, envVarsJSON string) error {
 }
 
 type envVariable struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
+	Name      string           `json:"name"`
+	Value     string           `json:"value"`
+	ValueFrom *json.RawMessage `json:"valueFrom,omitempty"`
 }
@@ -32,16 +32,47 @@ func testPlatformEnvVarsSetup(t *testing.T, when spec.G, it spec.S) {
 
 	when("#setup", func() {
 		it("writes all env var files to the platform dir", func() {
-			err := cnb.SetupPlatformEnvVars(testVolume, `[{"name": "keyA", "value": "valueA"}, {"name": "keyB", "value": "valueB"}, {"name": "keyC", "value": "valueC"}]`)
+			err := cnb.SetupPlatformEnvVars(testVolume, `[{"name": "keyA", "value": "valueA"}, {"name": "keyB", "value": "valueB"}, {"name": "keyC", "value": "valueC"}]`, noEnv)
 			require.NoError(t, err)
 
 			checkEnvVar(t, testVolume, "keyA", "valueA")
 			checkEnvVar(t, testVolume, "keyB", "valueB")
 			checkEnvVar(t, testVolume, "keyC", "valueC")
 		})
+
+		it("writes the resolved values of env vars sourced from secrets and config maps", func() {
+			lookupEnv := func(key string) (string, bool) {
+				if key == "PLATFORM_ENV_NPM_TOKEN" {
+					return "secret-token", true
+				}
+				return "", false
+			}
+
+			err := cnb.SetupPlatformEnvVars(testVolume, `[{"name": "keyA", "value": "valueA"}, {"name": "NPM_TOKEN", "value": "", "valueFrom": {"secretKeyRef": {"name": "npm", "key": "token"}}}]`, lookupEnv)
+			require.NoError(t, err)
+
+			checkEnvVar(t, testVolume, "keyA", "valueA")
+			checkEnvVar(t, testVolume, "NPM_TOKEN", "secret-token")
+		})
+
+		it("skips env vars with optional references that were not resolved", func() {
+			err := cnb.SetupPlatformEnvVars(testVolume, `[{"name": "OPTIONAL", "value": "", "valueFrom": {"configMapKeyRef": {"name": "config", "key": "missing", "optional": true}}}]`, noEnv)
+			require.NoError(t, err)
+
+			require.False(t, fileExists(path.Join(testVolume, "env", "OPTIONAL")))
+		})
 	})
 }
 
+func noEnv(string) (string, bool) {
+	return "", false
+}
+
+func fileExists(file string) bool {
+	_, err := os.Stat(file)
+	return err == nil
+}
+
 func checkEnvVar(t *testing.T, testVolume, key, value string) {
 	require.FileExists(t, path.Join(testVolume, "env", key))
 	buf, err := ioutil.ReadFile(path.Join(testVolume, "env", key))
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	ConfigName = "kpack-config"
+
+	MaxRoutinesPerController = 16
+
+	resyncPeriodKey            = "resyncPeriod"
+	sourcePollingFrequencyKey  = "sourcePollingFrequency"
+	builderPollingFrequencyKey = "builderPollingFrequency"
+	builderPollingBackoffKey   = "builderPollingBackoff"
+	routinesPerControllerKey   = "routinesPerController"
+	buildHistoryLimitKey       = "buildHistoryLimit"
+	cacheSizeKey               = "defaultCacheSize"
+	logLevelKey                = "logLevel"
+)
+
+// Config holds the controller settings read from the kpack-config ConfigMap.
+// ResyncPeriod is only read when the controller starts.
+type Config struct {
+	ResyncPeriod            time.Duration
+	SourcePollingFrequency  time.Duration
+	BuilderPollingFrequency time.Duration
+	BuilderPollingBackoff   time.Duration
+	RoutinesPerController   int
+	BuildHistoryLimit       int64
+	CacheSize               *resource.Quantity
+	LogLevel                zapcore.Level
+}
+
+func Default() *Config {
+	return &Config{
+		ResyncPeriod:            10 * time.Hour,
+		SourcePollingFrequency:  1 * time.Minute,
+		BuilderPollingFrequency: 1 * time.Minute,
+		BuilderPollingBackoff:   30 * time.Minute,
+		RoutinesPerController:   2,
+		BuildHistoryLimit:       10,
+		LogLevel:                zapcore.InfoLevel,
+	}
+}
+
+// NewConfigFromConfigMap applies the values in the ConfigMap to the defaults. Keys that are not set keep their default.
+func NewConfigFromConfigMap(configMap *corev1.ConfigMap) (*Config, error) {
+	config := Default()
+
+	durations := map[string]*time.Duration{
+		resyncPeriodKey:            &config.ResyncPeriod,
+		sourcePollingFrequencyKey:  &config.SourcePollingFrequency,
+		builderPollingFrequencyKey: &config.BuilderPollingFrequency,
+		builderPollingBackoffKey:   &config.BuilderPollingBackoff,
+	}
+	for key, duration := range durations {
+		value, ok := configMap.Data[key]
+		if !ok {
+			continue
+		}
+
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %s", key, value, err)
+		}
+		if parsed <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be greater than zero", key, value)
+		}
+		*duration = parsed
+	}
+
+	if value, ok := configMap.Data[routinesPerControllerKey]; ok {
+		routines, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %s", routinesPerControllerKey, value, err)
+		}
+		if routines < 1 || routines > MaxRoutinesPerController {
+			return nil, fmt.Errorf("invalid %s %q: must be between 1 and %d", routinesPerControllerKey, value, MaxRoutinesPerController)
+		}
+		config.RoutinesPerController = routines
+	}
+
+	if value, ok := configMap.Data[buildHistoryLimitKey]; ok {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %s", buildHistoryLimitKey, value, err)
+		}
+		if limit < 1 {
+			return nil, fmt.Errorf("invalid %s %q: must be at least 1", buildHistoryLimitKey, value)
+		}
+		config.BuildHistoryLimit = limit
+	}
+
+	if value, ok := configMap.Data[cacheSizeKey]; ok && value != "" {
+		size, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %s", cacheSizeKey, value, err)
+		}
+		if size.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be greater than zero", cacheSizeKey, value)
+		}
+		config.CacheSize = &size
+	}
+
+	if value, ok := configMap.Data[logLevelKey]; ok {
+		if err := config.LogLevel.UnmarshalText([]byte(value)); err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %s", logLevelKey, value, err)
+		}
+	}
+
+	return config, nil
+}
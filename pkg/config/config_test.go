@@ -0,0 +1,131 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pivotal/kpack/pkg/config"
+)
+
+func TestConfig(t *testing.T) {
+	spec.Run(t, "Config", testConfig)
+}
+
+func testConfig(t *testing.T, when spec.G, it spec.S) {
+	configMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      config.ConfigName,
+				Namespace: "kpack",
+			},
+			Data: data,
+		}
+	}
+
+	when("#NewConfigFromConfigMap", func() {
+		it("uses the defaults for an empty config map", func() {
+			cfg, err := config.NewConfigFromConfigMap(configMap(nil))
+			require.NoError(t, err)
+
+			assert.Equal(t, config.Default(), cfg)
+		})
+
+		it("parses all values", func() {
+			cfg, err := config.NewConfigFromConfigMap(configMap(map[string]string{
+				"resyncPeriod":            "5h",
+				"sourcePollingFrequency":  "2m",
+				"builderPollingFrequency": "3m",
+				"builderPollingBackoff":   "1h",
+				"routinesPerController":   "4",
+				"buildHistoryLimit":       "3",
+				"defaultCacheSize":        "2G",
+				"logLevel":                "debug",
+			}))
+			require.NoError(t, err)
+
+			cacheSize := resource.MustParse("2G")
+			assert.Equal(t, &config.Config{
+				ResyncPeriod:            5 * time.Hour,
+				SourcePollingFrequency:  2 * time.Minute,
+				BuilderPollingFrequency: 3 * time.Minute,
+				BuilderPollingBackoff:   time.Hour,
+				RoutinesPerController:   4,
+				BuildHistoryLimit:       3,
+				CacheSize:               &cacheSize,
+				LogLevel:                zapcore.DebugLevel,
+			}, cfg)
+		})
+
+		it("treats an empty cache size as no default cache", func() {
+			cfg, err := config.NewConfigFromConfigMap(configMap(map[string]string{
+				"defaultCacheSize": "",
+			}))
+			require.NoError(t, err)
+
+			assert.Nil(t, cfg.CacheSize)
+		})
+
+		for key, value := range map[string]string{
+			"sourcePollingFrequency": "often",
+			"builderPollingBackoff":  "-1m",
+			"routinesPerController":  "0",
+			"buildHistoryLimit":      "none",
+			"defaultCacheSize":       "0",
+			"logLevel":               "verbose",
+		} {
+			key, value := key, value
+			it("rejects an invalid "+key, func() {
+				_, err := config.NewConfigFromConfigMap(configMap(map[string]string{
+					key: value,
+				}))
+				assert.Error(t, err)
+			})
+		}
+
+		it("rejects more routines than the maximum", func() {
+			_, err := config.NewConfigFromConfigMap(configMap(map[string]string{
+				"routinesPerController": "17",
+			}))
+			assert.EqualError(t, err, `invalid routinesPerController "17": must be between 1 and 16`)
+		})
+	})
+
+	when("Store", func() {
+		store := config.NewStore(zap.NewNop().Sugar(), config.Default())
+
+		var notified []*config.Config
+		store.OnChange(func(cfg *config.Config) {
+			notified = append(notified, cfg)
+		})
+
+		it("loads and notifies the new config when the config map changes", func() {
+			store.OnConfigChanged(configMap(map[string]string{
+				"sourcePollingFrequency": "5m",
+			}))
+
+			assert.Equal(t, 5*time.Minute, store.Load().SourcePollingFrequency)
+			assert.Equal(t, []*config.Config{store.Load()}, notified)
+		})
+
+		it("keeps the previous config when the config map is invalid", func() {
+			store.OnConfigChanged(configMap(map[string]string{
+				"sourcePollingFrequency": "5m",
+			}))
+			store.OnConfigChanged(configMap(map[string]string{
+				"sourcePollingFrequency": "sometimes",
+			}))
+
+			assert.Equal(t, 5*time.Minute, store.Load().SourcePollingFrequency)
+			assert.Len(t, notified, 1)
+		})
+	})
+}
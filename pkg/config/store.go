@@ -0,0 +1,53 @@
+package config
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Store holds the latest valid Config so that reconcilers pick up changes to the kpack-config ConfigMap without a restart.
+type Store struct {
+	logger *zap.SugaredLogger
+	config atomic.Value
+
+	mu        sync.Mutex
+	listeners []func(*Config)
+}
+
+func NewStore(logger *zap.SugaredLogger, config *Config) *Store {
+	s := &Store{logger: logger}
+	s.config.Store(config)
+	return s
+}
+
+func (s *Store) Load() *Config {
+	return s.config.Load().(*Config)
+}
+
+// OnChange registers a func that is called with the new Config each time the ConfigMap changes.
+func (s *Store) OnChange(listener func(*Config)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+// OnConfigChanged is a configmap.Observer. An invalid ConfigMap is logged and the previous Config is kept.
+func (s *Store) OnConfigChanged(configMap *corev1.ConfigMap) {
+	config, err := NewConfigFromConfigMap(configMap)
+	if err != nil {
+		s.logger.Errorw("Ignoring invalid config", "configmap", configMap.Name, zap.Error(err))
+		return
+	}
+
+	s.config.Store(config)
+	s.logger.Infow("Loaded config", "configmap", configMap.Name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, listener := range s.listeners {
+		listener(config)
+	}
+}
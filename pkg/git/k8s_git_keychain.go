@@ -2,8 +2,9 @@ package git
 
 import (
 	"fmt"
-	"net/url"
 
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	k8sclient "k8s.io/client-go/kubernetes"
 
@@ -11,6 +12,11 @@ import (
 	"github.com/pivotal/kpack/pkg/secret"
 )
 
+const (
+	knownHostsKey  = "known_hosts"
+	defaultSSHUser = "git"
+)
+
 type k8sGitKeychain struct {
 	secretManager secret.SecretManager
 }
@@ -28,7 +34,7 @@ func (k *k8sGitKeychain) Resolve(namespace, serviceAccount string, git v1alpha1.
 		return anonymousAuth{}, nil
 	}
 
-	creds, err := k.secretManager.SecretForServiceAccountAndURL(serviceAccount, namespace, git.URL)
+	secret, err := k.secretManager.MatchingSecretForServiceAccount(serviceAccount, namespace, git.URL)
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return nil, err
 	}
@@ -36,8 +42,26 @@ func (k *k8sGitKeychain) Resolve(namespace, serviceAccount string, git v1alpha1.
 		return anonymousAuth{}, nil
 	}
 
-	return basicAuth{Username: creds.Username, Password: creds.Password}, nil
+	if secret.Type == corev1.SecretTypeSSHAuth {
+		return sshAuth{
+			User:       sshUser(git.URL),
+			PrivateKey: secret.Data[corev1.SSHAuthPrivateKey],
+			KnownHosts: secret.Data[knownHostsKey],
+		}, nil
+	}
+
+	return basicAuth{
+		Username: string(secret.Data[corev1.BasicAuthUsernameKey]),
+		Password: string(secret.Data[corev1.BasicAuthPasswordKey]),
+	}, nil
+}
 
+func sshUser(gitURL string) string {
+	endpoint, err := transport.NewEndpoint(gitURL)
+	if err != nil || endpoint.User == "" {
+		return defaultSSHUser
+	}
+	return endpoint.User
 }
 
 var matchingDomains = []string{
@@ -46,16 +70,18 @@ var matchingDomains = []string{
 	// Allow scheme-prefixed.
 	"https://%s",
 	"http://%s",
+	"ssh://%s",
 }
 
 func gitUrlMatch(urlMatch, annotatedUrl string) bool {
-	parseURL, err := url.Parse(urlMatch)
+	// parses scp-like ssh urls such as git@github.com:org/repo.git as well as regular urls
+	endpoint, err := transport.NewEndpoint(urlMatch)
 	if err != nil {
 		return false
 	}
 
 	for _, format := range matchingDomains {
-		if fmt.Sprintf(format, parseURL.Hostname()) == annotatedUrl {
+		if fmt.Sprintf(format, endpoint.Host) == annotatedUrl {
 			return true
 		}
 	}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/sclevine/spec"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	k8sTesting "k8s.io/client-go/testing"
@@ -77,6 +79,65 @@ func test(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("ssh secrets", func() {
+			const sshServiceAccount = "some-ssh-service-account"
+
+			it.Before(func() {
+				_, err := fakeClient.CoreV1().Secrets("some-namespace").Create(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "ssh-secret",
+						Annotations: map[string]string{
+							v1alpha1.GITSecretAnnotationPrefix: "github.com",
+						},
+					},
+					Data: map[string][]byte{
+						corev1.SSHAuthPrivateKey: []byte("some-private-key"),
+						"known_hosts":            []byte("some-known-hosts"),
+					},
+					Type: corev1.SecretTypeSSHAuth,
+				})
+				require.NoError(t, err)
+
+				_, err = fakeClient.CoreV1().ServiceAccounts("some-namespace").Create(&corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: sshServiceAccount,
+					},
+					Secrets: []corev1.ObjectReference{
+						{Name: "ssh-secret"},
+					},
+				})
+				require.NoError(t, err)
+			})
+
+			it("returns ssh auth for scp-like urls", func() {
+				auth, err := keychain.Resolve("some-namespace", sshServiceAccount, v1alpha1.Git{
+					URL:      "git@github.com:org/repo.git",
+					Revision: "master",
+				})
+				require.NoError(t, err)
+
+				require.Equal(t, auth, sshAuth{
+					User:       "git",
+					PrivateKey: []byte("some-private-key"),
+					KnownHosts: []byte("some-known-hosts"),
+				})
+			})
+
+			it("returns ssh auth with the user from ssh urls", func() {
+				auth, err := keychain.Resolve("some-namespace", sshServiceAccount, v1alpha1.Git{
+					URL:      "ssh://some-user@github.com/org/repo.git",
+					Revision: "master",
+				})
+				require.NoError(t, err)
+
+				require.Equal(t, auth, sshAuth{
+					User:       "some-user",
+					PrivateKey: []byte("some-private-key"),
+					KnownHosts: []byte("some-known-hosts"),
+				})
+			})
+		})
+
 		it("returns anonymous auth for no matching secret", func() {
 			auth, err := keychain.Resolve("some-namespace", serviceAccount, v1alpha1.Git{
 				URL:      "https://no-creds-github.com/org/repo",
@@ -1,11 +1,24 @@
 package git
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	gitssh "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
 	"gopkg.in/src-d/go-git.v4/storage/memory"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
@@ -14,7 +27,7 @@ import (
 const defaultRemote = "origin"
 
 type auth interface {
-	auth() transport.AuthMethod
+	auth() (transport.AuthMethod, error)
 }
 
 type basicAuth struct {
@@ -22,48 +35,113 @@ type basicAuth struct {
 	Password string
 }
 
-func (b basicAuth) auth() transport.AuthMethod {
+func (b basicAuth) auth() (transport.AuthMethod, error) {
 	return &http.BasicAuth{
 		Username: b.Username,
 		Password: b.Password,
+	}, nil
+}
+
+type sshAuth struct {
+	User       string
+	PrivateKey []byte
+	KnownHosts []byte
+}
+
+func (s sshAuth) auth() (transport.AuthMethod, error) {
+	signer, err := ssh.ParsePrivateKey(s.PrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse ssh private key")
+	}
+
+	hostKeyCallback, err := knownHostsCallback(s.KnownHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitssh.PublicKeys{
+		User:   s.User,
+		Signer: signer,
+		HostKeyCallbackHelper: gitssh.HostKeyCallbackHelper{
+			HostKeyCallback: hostKeyCallback,
+		},
+	}, nil
+}
+
+// knownHostsCallback verifies ssh host keys against the known_hosts of the git secret.
+// Connections to hosts without a known host key are refused.
+func knownHostsCallback(knownHosts []byte) (ssh.HostKeyCallback, error) {
+	if len(knownHosts) == 0 {
+		return nil, errors.New("ssh git secret must provide known_hosts")
+	}
+
+	file, err := ioutil.TempFile("", "known_hosts")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	_, err = file.Write(knownHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	callback, err := knownhosts.New(file.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse known_hosts")
 	}
+	return callback, nil
 }
 
 type anonymousAuth struct {
 }
 
-func (anonymousAuth) auth() transport.AuthMethod {
-	return nil
+func (anonymousAuth) auth() (transport.AuthMethod, error) {
+	return nil, nil
 }
 
 type remoteGitResolver struct {
 }
 
-func (*remoteGitResolver) Resolve(auth auth, sourceConfig v1alpha1.SourceConfig) (v1alpha1.ResolvedSourceConfig, error) {
+// Resolve resolves the revision of sourceConfig. The tree hash of filtered paths is reused from previous, the
+// source last resolved for the same config, when the revision has not moved so that polling does not clone.
+func (*remoteGitResolver) Resolve(auth auth, sourceConfig v1alpha1.SourceConfig, previous *v1alpha1.ResolvedGitSource) (v1alpha1.ResolvedSourceConfig, error) {
 	repo := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
 		Name: defaultRemote,
 		URLs: []string{sourceConfig.Git.URL},
 	})
+	authMethod, err := auth.auth()
+	if err != nil {
+		return v1alpha1.ResolvedSourceConfig{}, err
+	}
+
 	references, err := repo.List(&git.ListOptions{
-		Auth: auth.auth(),
+		Auth: authMethod,
 	})
 	if err != nil {
-		return v1alpha1.ResolvedSourceConfig{
-			Git: &v1alpha1.ResolvedGitSource{
-				URL:      sourceConfig.Git.URL,
-				Revision: sourceConfig.Git.Revision, // maybe
-				Type:     v1alpha1.Unknown,
-				SubPath:  sourceConfig.SubPath,
-			},
-		}, nil
+		return v1alpha1.ResolvedSourceConfig{}, errors.Wrapf(err, "unable to fetch references for %s", sourceConfig.Git.URL)
 	}
 
 	for _, ref := range references {
 		if string(ref.Name().Short()) == sourceConfig.Git.Revision {
+			var treeHash string
+			if sourceConfig.Git.FilterPaths {
+				if unchangedRevision(previous, sourceConfig, ref) {
+					treeHash = previous.TreeHash
+				} else {
+					treeHash, err = filteredTreeHash(sourceConfig.Git.URL, authMethod, ref, watchedPaths(sourceConfig))
+					if err != nil {
+						return v1alpha1.ResolvedSourceConfig{}, errors.Wrapf(err, "unable to fetch tree for %s", sourceConfig.Git.URL)
+					}
+				}
+			}
+
 			return v1alpha1.ResolvedSourceConfig{
 				Git: &v1alpha1.ResolvedGitSource{
 					URL:      sourceConfig.Git.URL,
 					Revision: ref.Hash().String(),
+					TreeHash: treeHash,
 					Type:     sourceType(ref),
 					SubPath:  sourceConfig.SubPath,
 				},
@@ -81,6 +159,14 @@ func (*remoteGitResolver) Resolve(auth auth, sourceConfig v1alpha1.SourceConfig)
 	}, nil
 }
 
+func unchangedRevision(previous *v1alpha1.ResolvedGitSource, sourceConfig v1alpha1.SourceConfig, ref *plumbing.Reference) bool {
+	return previous != nil &&
+		previous.TreeHash != "" &&
+		previous.URL == sourceConfig.Git.URL &&
+		previous.SubPath == sourceConfig.SubPath &&
+		previous.Revision == ref.Hash().String()
+}
+
 func sourceType(reference *plumbing.Reference) v1alpha1.GitSourceKind {
 	switch {
 	case reference.Name().IsBranch():
@@ -91,3 +177,74 @@ func sourceType(reference *plumbing.Reference) v1alpha1.GitSourceKind {
 		return v1alpha1.Unknown
 	}
 }
+
+func watchedPaths(sourceConfig v1alpha1.SourceConfig) []string {
+	paths := make([]string, 0, len(sourceConfig.Git.WatchPaths)+1)
+	for _, path := range append([]string{sourceConfig.SubPath}, sourceConfig.Git.WatchPaths...) {
+		paths = append(paths, strings.Trim(path, "/"))
+	}
+	sort.Strings(paths[1:])
+	return paths
+}
+
+// filteredTreeHash identifies the content of paths at the commit of ref. A single path is identified
+// by its git tree hash and multiple paths by a sha256 of their tree hashes. Paths missing from the
+// commit are identified by the zero hash so that deleting a watched path is a change.
+func filteredTreeHash(url string, authMethod transport.AuthMethod, ref *plumbing.Reference, paths []string) (string, error) {
+	repository, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           url,
+		Auth:          authMethod,
+		ReferenceName: ref.Name(),
+		SingleBranch:  true,
+		Depth:         1,
+		Tags:          git.NoTags,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var commit *object.Commit
+	if tag, err := repository.TagObject(ref.Hash()); err == nil {
+		commit, err = tag.Commit()
+		if err != nil {
+			return "", err
+		}
+	} else {
+		commit, err = repository.CommitObject(ref.Hash())
+		if err != nil {
+			return "", err
+		}
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", err
+	}
+
+	hashes := make([]plumbing.Hash, 0, len(paths))
+	for _, path := range paths {
+		if path == "" {
+			hashes = append(hashes, tree.Hash)
+			continue
+		}
+
+		entry, err := tree.FindEntry(path)
+		if err == object.ErrEntryNotFound || err == object.ErrDirectoryNotFound {
+			hashes = append(hashes, plumbing.ZeroHash)
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		hashes = append(hashes, entry.Hash)
+	}
+
+	if len(hashes) == 1 {
+		return hashes[0].String(), nil
+	}
+
+	sum := sha256.New()
+	for i, hash := range hashes {
+		fmt.Fprintf(sum, "%s %s\n", paths[i], hash)
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
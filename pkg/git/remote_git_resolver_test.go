@@ -1,6 +1,10 @@
 package git
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 
 	"github.com/sclevine/spec"
@@ -36,7 +40,7 @@ func testRemoteGitResolver(t *testing.T, when spec.G, it spec.S) {
 						Revision: nonHEADCommit,
 					},
 					SubPath: "/foo/bar",
-				})
+				}, nil)
 				require.NoError(t, err)
 
 				assert.Equal(t, resolvedGitSource, v1alpha1.ResolvedSourceConfig{
@@ -62,7 +66,7 @@ func testRemoteGitResolver(t *testing.T, when spec.G, it spec.S) {
 						Revision: "master",
 					},
 					SubPath: "/foo/bar",
-				})
+				}, nil)
 				require.NoError(t, err)
 
 				assert.Equal(t, resolvedGitSource, v1alpha1.ResolvedSourceConfig{
@@ -88,7 +92,7 @@ func testRemoteGitResolver(t *testing.T, when spec.G, it spec.S) {
 						Revision: tag,
 					},
 					SubPath: "/foo/bar",
-				})
+				}, nil)
 				require.NoError(t, err)
 
 				assert.Equal(t, resolvedGitSource, v1alpha1.ResolvedSourceConfig{
@@ -102,13 +106,106 @@ func testRemoteGitResolver(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("paths are filtered", func() {
+			resolve := func(subPath string, watchPaths ...string) *v1alpha1.ResolvedGitSource {
+				repo := fixtures.Basic().One()
+
+				gitResolver := &remoteGitResolver{}
+
+				resolvedGitSource, err := gitResolver.Resolve(anonymousAuth{}, v1alpha1.SourceConfig{
+					Git: &v1alpha1.Git{
+						URL:         repo.URL,
+						Revision:    "master",
+						FilterPaths: true,
+						WatchPaths:  watchPaths,
+					},
+					SubPath: subPath,
+				}, nil)
+				require.NoError(t, err)
+
+				assert.Equal(t, fixtureHEADMasterCommit, resolvedGitSource.Git.Revision)
+				return resolvedGitSource.Git
+			}
+
+			it("returns the tree hash of the sub path", func() {
+				goTree := resolve("go")
+				assert.Len(t, goTree.TreeHash, 40)
+				assert.Equal(t, goTree.TreeHash, resolve("/go/").TreeHash)
+				assert.NotEqual(t, goTree.TreeHash, resolve("json").TreeHash)
+				assert.NotEqual(t, goTree.TreeHash, resolve("").TreeHash)
+			})
+
+			it("returns the zero hash for missing paths", func() {
+				assert.Equal(t, "0000000000000000000000000000000000000000", resolve("not-a-path").TreeHash)
+			})
+
+			it("combines the tree hashes of the watch paths", func() {
+				combined := resolve("go", "json", "php")
+				assert.Len(t, combined.TreeHash, 64)
+				assert.Equal(t, combined.TreeHash, resolve("go", "php", "json").TreeHash)
+				assert.NotEqual(t, combined.TreeHash, resolve("go", "json").TreeHash)
+			})
+
+			it("reuses the previous tree hash when the revision has not changed", func() {
+				repo := fixtures.Basic().One()
+
+				gitResolver := &remoteGitResolver{}
+
+				sourceConfig := v1alpha1.SourceConfig{
+					Git: &v1alpha1.Git{
+						URL:         repo.URL,
+						Revision:    "master",
+						FilterPaths: true,
+					},
+					SubPath: "go",
+				}
+
+				resolvedGitSource, err := gitResolver.Resolve(anonymousAuth{}, sourceConfig, &v1alpha1.ResolvedGitSource{
+					URL:      repo.URL,
+					Revision: fixtureHEADMasterCommit,
+					TreeHash: "previous-tree-hash",
+					SubPath:  "go",
+					Type:     v1alpha1.Branch,
+				})
+				require.NoError(t, err)
+				assert.Equal(t, "previous-tree-hash", resolvedGitSource.Git.TreeHash)
+
+				resolvedGitSource, err = gitResolver.Resolve(anonymousAuth{}, sourceConfig, &v1alpha1.ResolvedGitSource{
+					URL:      repo.URL,
+					Revision: nonHEADCommit,
+					TreeHash: "previous-tree-hash",
+					SubPath:  "go",
+					Type:     v1alpha1.Branch,
+				})
+				require.NoError(t, err)
+				assert.Equal(t, resolve("go").TreeHash, resolvedGitSource.Git.TreeHash)
+			})
+
+			it("does not return a tree hash without path filtering", func() {
+				repo := fixtures.Basic().One()
+
+				gitResolver := &remoteGitResolver{}
+
+				resolvedGitSource, err := gitResolver.Resolve(anonymousAuth{}, v1alpha1.SourceConfig{
+					Git: &v1alpha1.Git{
+						URL:      repo.URL,
+						Revision: "master",
+					},
+					SubPath: "go",
+				}, nil)
+				require.NoError(t, err)
+
+				assert.Equal(t, "", resolvedGitSource.Git.TreeHash)
+			})
+		})
+
 		when("authentication fails", func() {
-			it("returns an unknown type", func() {
+			it("returns the error", func() {
 				repo := fixtures.ByTag("tags").One()
 
 				gitResolver := &remoteGitResolver{}
 
-				resolvedGitSource, err := gitResolver.Resolve(basicAuth{
+				_, err := gitResolver.Resolve(basicAuth{
 					Username: "notgonna",
 					Password: "work",
 				}, v1alpha1.SourceConfig{
@@ -117,17 +214,48 @@ func testRemoteGitResolver(t *testing.T, when spec.G, it spec.S) {
 						Revision: tag,
 					},
 					SubPath: "/foo/bar",
-				})
+				}, nil)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unable to fetch references for "+repo.URL)
+			})
+		})
+
+		when("ssh auth is invalid", func() {
+			it("returns an error for an unparsable private key", func() {
+				gitResolver := &remoteGitResolver{}
+
+				_, err := gitResolver.Resolve(sshAuth{
+					User:       "git",
+					PrivateKey: []byte("not-a-private-key"),
+					KnownHosts: []byte("github.com ssh-rsa AAAA"),
+				}, v1alpha1.SourceConfig{
+					Git: &v1alpha1.Git{
+						URL:      "git@github.com:org/repo.git",
+						Revision: "master",
+					},
+				}, nil)
+				require.Error(t, err)
+			})
+
+			it("returns an error when known_hosts are missing", func() {
+				key, err := rsa.GenerateKey(rand.Reader, 1024)
 				require.NoError(t, err)
 
-				assert.Equal(t, resolvedGitSource, v1alpha1.ResolvedSourceConfig{
-					Git: &v1alpha1.ResolvedGitSource{
-						URL:      repo.URL,
-						Revision: tag,
-						Type:     v1alpha1.Unknown,
-						SubPath:  "/foo/bar",
+				gitResolver := &remoteGitResolver{}
+
+				_, err = gitResolver.Resolve(sshAuth{
+					User: "git",
+					PrivateKey: pem.EncodeToMemory(&pem.Block{
+						Type:  "RSA PRIVATE KEY",
+						Bytes: x509.MarshalPKCS1PrivateKey(key),
+					}),
+				}, v1alpha1.SourceConfig{
+					Git: &v1alpha1.Git{
+						URL:      "git@github.com:org/repo.git",
+						Revision: "master",
 					},
-				})
+				}, nil)
+				require.EqualError(t, err, "ssh git secret must provide known_hosts")
 			})
 		})
 	})
@@ -24,7 +24,15 @@ func (r *Resolver) Resolve(sourceResolver *v1alpha1.SourceResolver) (v1alpha1.Re
 		return v1alpha1.ResolvedSourceConfig{}, err
 	}
 
-	return r.remoteGitResolver.Resolve(auth, sourceResolver.Spec.Source)
+	return r.remoteGitResolver.Resolve(auth, sourceResolver.Spec.Source, previousSource(sourceResolver))
+}
+
+// previousSource returns the last resolved git source if it was resolved from the current spec.
+func previousSource(sourceResolver *v1alpha1.SourceResolver) *v1alpha1.ResolvedGitSource {
+	if sourceResolver.Status.ObservedGeneration != sourceResolver.Generation || sourceResolver.ResolutionFailed() {
+		return nil
+	}
+	return sourceResolver.Status.Source.Git
 }
 
 func (*Resolver) CanResolve(sourceResolver *v1alpha1.SourceResolver) bool {
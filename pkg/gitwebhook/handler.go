@@ -0,0 +1,139 @@
+package gitwebhook
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	v1alpha1listers "github.com/pivotal/kpack/pkg/client/listers/build/v1alpha1"
+)
+
+const maxPayloadSize = 25 * 1024 * 1024
+
+// Handler receives push events from git hosts and immediately enqueues the
+// source resolvers tracking a pushed repository revision.
+type Handler struct {
+	Logger               *zap.SugaredLogger
+	Secret               []byte
+	SourceResolverLister v1alpha1listers.SourceResolverLister
+	Enqueue              func(obj interface{})
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	provider, ok := providerFor(r.Header)
+	if !ok {
+		http.Error(w, "unsupported git provider", http.StatusBadRequest)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "unable to read payload", http.StatusBadRequest)
+		return
+	}
+
+	if len(h.Secret) == 0 || !provider.verify(r.Header, body, h.Secret) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := provider.pushEvent(r.Header, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if event == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	enqueued, err := h.enqueueMatching(event)
+	if err != nil {
+		h.Logger.Errorw("Unable to enqueue source resolvers for git push", zap.Error(err))
+		http.Error(w, "unable to enqueue source resolvers", http.StatusInternalServerError)
+		return
+	}
+
+	h.Logger.Infof("Enqueued %d source resolvers for push to %s", enqueued, strings.Join(event.refs, ", "))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) enqueueMatching(event *pushEvent) (int, error) {
+	sourceResolvers, err := h.SourceResolverLister.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+
+	enqueued := 0
+	for _, sourceResolver := range sourceResolvers {
+		if event.matches(sourceResolver) {
+			h.Enqueue(sourceResolver)
+			enqueued++
+		}
+	}
+	return enqueued, nil
+}
+
+type pushEvent struct {
+	repositoryURLs []string
+	refs           []string
+}
+
+func (e *pushEvent) matches(sourceResolver *v1alpha1.SourceResolver) bool {
+	if !sourceResolver.IsGit() {
+		return false
+	}
+	git := sourceResolver.Spec.Source.Git
+
+	return e.matchesRepository(git.URL) && e.matchesRevision(git.Revision)
+}
+
+func (e *pushEvent) matchesRepository(url string) bool {
+	repository := normalizeRepositoryURL(url)
+	if repository == "" {
+		return false
+	}
+
+	for _, pushedURL := range e.repositoryURLs {
+		if normalizeRepositoryURL(pushedURL) == repository {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *pushEvent) matchesRevision(revision string) bool {
+	for _, ref := range e.refs {
+		if ref == revision ||
+			strings.TrimPrefix(ref, "refs/heads/") == revision ||
+			strings.TrimPrefix(ref, "refs/tags/") == revision {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRepositoryURL reduces https, ssh and scp-like git urls to host/path
+// so that a push to a repository matches regardless of the url used to clone it.
+func normalizeRepositoryURL(url string) string {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil || endpoint.Host == "" {
+		return ""
+	}
+
+	path := strings.Trim(endpoint.Path, "/")
+	path = strings.TrimSuffix(path, ".git")
+	return strings.ToLower(endpoint.Host + "/" + path)
+}
@@ -0,0 +1,236 @@
+package gitwebhook_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pivotal/kpack/pkg/gitwebhook"
+	"github.com/pivotal/kpack/pkg/reconciler/testhelpers"
+)
+
+func TestHandler(t *testing.T) {
+	spec.Run(t, "Git Webhook Handler", testHandler)
+}
+
+func testHandler(t *testing.T, when spec.G, it spec.S) {
+	const secret = "some-webhook-secret"
+
+	var (
+		enqueued []string
+		server   *httptest.Server
+	)
+
+	it.Before(func() {
+		listers := testhelpers.NewListers([]runtime.Object{
+			gitSourceResolver("github-https", "https://github.com/some-org/some-repo", "master"),
+			gitSourceResolver("github-ssh", "git@github.com:some-org/some-repo.git", "master"),
+			gitSourceResolver("github-other-branch", "https://github.com/some-org/some-repo", "some-branch"),
+			gitSourceResolver("github-other-repo", "https://github.com/some-org/other-repo", "master"),
+			gitSourceResolver("gitlab", "https://gitlab.com/some-group/some-repo.git", "master"),
+			gitSourceResolver("bitbucket-cloud", "https://bitbucket.org/some-team/some-repo.git", "master"),
+			gitSourceResolver("bitbucket-cloud-deleted-branch", "https://bitbucket.org/some-team/some-repo.git", "removed-branch"),
+			gitSourceResolver("bitbucket-server", "https://bitbucket.example.com/scm/proj/some-repo.git", "v1.0.0"),
+			&v1alpha1.SourceResolver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "blob",
+					Namespace: "some-namespace",
+				},
+				Spec: v1alpha1.SourceResolverSpec{
+					Source: v1alpha1.SourceConfig{
+						Blob: &v1alpha1.Blob{
+							URL: "https://github.com/some-org/some-repo",
+						},
+					},
+				},
+			},
+		})
+
+		enqueued = nil
+		server = httptest.NewServer(&gitwebhook.Handler{
+			Logger:               zap.NewNop().Sugar(),
+			Secret:               []byte(secret),
+			SourceResolverLister: listers.GetSourceResolverLister(),
+			Enqueue: func(obj interface{}) {
+				enqueued = append(enqueued, obj.(*v1alpha1.SourceResolver).Name)
+			},
+		})
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	post := func(payload string, headers map[string]string) *http.Response {
+		request, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(readPayload(t, payload)))
+		require.NoError(t, err)
+
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		return response
+	}
+
+	when("github", func() {
+		it("enqueues source resolvers tracking the pushed branch", func() {
+			response := post("github_push.json", map[string]string{
+				"X-GitHub-Event":      "push",
+				"X-Hub-Signature-256": sign(t, "github_push.json", "sha256=", sha256.New, secret),
+			})
+
+			require.Equal(t, http.StatusAccepted, response.StatusCode)
+			require.Equal(t, []string{"github-https", "github-ssh"}, sorted(enqueued))
+		})
+
+		it("accepts sha1 signatures", func() {
+			response := post("github_push.json", map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": sign(t, "github_push.json", "sha1=", sha1.New, secret),
+			})
+
+			require.Equal(t, http.StatusAccepted, response.StatusCode)
+			require.Equal(t, []string{"github-https", "github-ssh"}, sorted(enqueued))
+		})
+
+		it("rejects invalid signatures", func() {
+			response := post("github_push.json", map[string]string{
+				"X-GitHub-Event":      "push",
+				"X-Hub-Signature-256": sign(t, "github_push.json", "sha256=", sha256.New, "wrong-secret"),
+			})
+
+			require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+			require.Empty(t, enqueued)
+		})
+
+		it("ignores events other than pushes", func() {
+			response := post("github_push.json", map[string]string{
+				"X-GitHub-Event":      "ping",
+				"X-Hub-Signature-256": sign(t, "github_push.json", "sha256=", sha256.New, secret),
+			})
+
+			require.Equal(t, http.StatusOK, response.StatusCode)
+			require.Empty(t, enqueued)
+		})
+	})
+
+	when("gitlab", func() {
+		it("enqueues source resolvers tracking the pushed branch", func() {
+			response := post("gitlab_push.json", map[string]string{
+				"X-Gitlab-Event": "Push Hook",
+				"X-Gitlab-Token": secret,
+			})
+
+			require.Equal(t, http.StatusAccepted, response.StatusCode)
+			require.Equal(t, []string{"gitlab"}, enqueued)
+		})
+
+		it("rejects invalid tokens", func() {
+			response := post("gitlab_push.json", map[string]string{
+				"X-Gitlab-Event": "Push Hook",
+				"X-Gitlab-Token": "wrong-secret",
+			})
+
+			require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+			require.Empty(t, enqueued)
+		})
+	})
+
+	when("bitbucket", func() {
+		it("enqueues source resolvers tracking the pushed branch on bitbucket cloud", func() {
+			response := post("bitbucket_cloud_push.json", map[string]string{
+				"X-Event-Key":     "repo:push",
+				"X-Hub-Signature": sign(t, "bitbucket_cloud_push.json", "sha256=", sha256.New, secret),
+			})
+
+			require.Equal(t, http.StatusAccepted, response.StatusCode)
+			require.Equal(t, []string{"bitbucket-cloud"}, enqueued)
+		})
+
+		it("enqueues source resolvers tracking the pushed tag on bitbucket server", func() {
+			response := post("bitbucket_server_push.json", map[string]string{
+				"X-Event-Key":     "repo:refs_changed",
+				"X-Hub-Signature": sign(t, "bitbucket_server_push.json", "sha256=", sha256.New, secret),
+			})
+
+			require.Equal(t, http.StatusAccepted, response.StatusCode)
+			require.Equal(t, []string{"bitbucket-server"}, enqueued)
+		})
+
+		it("rejects invalid signatures", func() {
+			response := post("bitbucket_cloud_push.json", map[string]string{
+				"X-Event-Key":     "repo:push",
+				"X-Hub-Signature": "sha256=invalid",
+			})
+
+			require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+			require.Empty(t, enqueued)
+		})
+	})
+
+	it("rejects requests from unknown providers", func() {
+		response := post("github_push.json", map[string]string{})
+
+		require.Equal(t, http.StatusBadRequest, response.StatusCode)
+		require.Empty(t, enqueued)
+	})
+
+	it("only accepts POST requests", func() {
+		response, err := http.Get(server.URL)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+	})
+}
+
+func gitSourceResolver(name, url, revision string) *v1alpha1.SourceResolver {
+	return &v1alpha1.SourceResolver{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "some-namespace",
+		},
+		Spec: v1alpha1.SourceResolverSpec{
+			Source: v1alpha1.SourceConfig{
+				Git: &v1alpha1.Git{
+					URL:      url,
+					Revision: revision,
+				},
+			},
+		},
+	}
+}
+
+func readPayload(t *testing.T, name string) []byte {
+	payload, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return payload
+}
+
+func sign(t *testing.T, payload, prefix string, hashFunc func() hash.Hash, secret string) string {
+	mac := hmac.New(hashFunc, []byte(secret))
+	mac.Write(readPayload(t, payload))
+	return prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func sorted(names []string) []string {
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,201 @@
+package gitwebhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type provider interface {
+	verify(header http.Header, body, secret []byte) bool
+	// pushEvent returns nil for events other than pushes
+	pushEvent(header http.Header, body []byte) (*pushEvent, error)
+}
+
+func providerFor(header http.Header) (provider, bool) {
+	switch {
+	case header.Get("X-GitHub-Event") != "":
+		return github{}, true
+	case header.Get("X-Gitlab-Event") != "":
+		return gitlab{}, true
+	case header.Get("X-Event-Key") != "":
+		return bitbucket{}, true
+	default:
+		return nil, false
+	}
+}
+
+type github struct{}
+
+func (github) verify(header http.Header, body, secret []byte) bool {
+	if signature := header.Get("X-Hub-Signature-256"); signature != "" {
+		return validSignature(signature, "sha256=", sha256.New, body, secret)
+	}
+	return validSignature(header.Get("X-Hub-Signature"), "sha1=", sha1.New, body, secret)
+}
+
+func (github) pushEvent(header http.Header, body []byte) (*pushEvent, error) {
+	if header.Get("X-GitHub-Event") != "push" {
+		return nil, nil
+	}
+
+	var payload struct {
+		Ref        string `json:"ref"`
+		Repository struct {
+			HTMLURL  string `json:"html_url"`
+			CloneURL string `json:"clone_url"`
+			SSHURL   string `json:"ssh_url"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.Wrap(err, "invalid github push payload")
+	}
+
+	return &pushEvent{
+		repositoryURLs: []string{payload.Repository.HTMLURL, payload.Repository.CloneURL, payload.Repository.SSHURL},
+		refs:           []string{payload.Ref},
+	}, nil
+}
+
+// gitlab does not sign payloads, it sends the configured secret token in a header instead
+type gitlab struct{}
+
+func (gitlab) verify(header http.Header, body, secret []byte) bool {
+	return hmac.Equal([]byte(header.Get("X-Gitlab-Token")), secret)
+}
+
+func (gitlab) pushEvent(header http.Header, body []byte) (*pushEvent, error) {
+	event := header.Get("X-Gitlab-Event")
+	if event != "Push Hook" && event != "Tag Push Hook" {
+		return nil, nil
+	}
+
+	var payload struct {
+		Ref     string `json:"ref"`
+		Project struct {
+			WebURL     string `json:"web_url"`
+			GitHTTPURL string `json:"git_http_url"`
+			GitSSHURL  string `json:"git_ssh_url"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.Wrap(err, "invalid gitlab push payload")
+	}
+
+	return &pushEvent{
+		repositoryURLs: []string{payload.Project.WebURL, payload.Project.GitHTTPURL, payload.Project.GitSSHURL},
+		refs:           []string{payload.Ref},
+	}, nil
+}
+
+// bitbucket handles pushes from both bitbucket cloud (repo:push) and bitbucket server (repo:refs_changed)
+type bitbucket struct{}
+
+func (bitbucket) verify(header http.Header, body, secret []byte) bool {
+	return validSignature(header.Get("X-Hub-Signature"), "sha256=", sha256.New, body, secret)
+}
+
+func (bitbucket) pushEvent(header http.Header, body []byte) (*pushEvent, error) {
+	switch header.Get("X-Event-Key") {
+	case "repo:push":
+		return bitbucketCloudPushEvent(body)
+	case "repo:refs_changed":
+		return bitbucketServerPushEvent(body)
+	default:
+		return nil, nil
+	}
+}
+
+func bitbucketCloudPushEvent(body []byte) (*pushEvent, error) {
+	var payload struct {
+		Push struct {
+			Changes []struct {
+				New *struct {
+					Type string `json:"type"`
+					Name string `json:"name"`
+				} `json:"new"`
+			} `json:"changes"`
+		} `json:"push"`
+		Repository struct {
+			Links struct {
+				HTML struct {
+					Href string `json:"href"`
+				} `json:"html"`
+			} `json:"links"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.Wrap(err, "invalid bitbucket push payload")
+	}
+
+	event := &pushEvent{
+		repositoryURLs: []string{payload.Repository.Links.HTML.Href},
+	}
+	for _, change := range payload.Push.Changes {
+		// deleted branches and tags have no new target
+		if change.New == nil {
+			continue
+		}
+
+		switch change.New.Type {
+		case "tag":
+			event.refs = append(event.refs, "refs/tags/"+change.New.Name)
+		default:
+			event.refs = append(event.refs, "refs/heads/"+change.New.Name)
+		}
+	}
+	return event, nil
+}
+
+func bitbucketServerPushEvent(body []byte) (*pushEvent, error) {
+	var payload struct {
+		Changes []struct {
+			RefID string `json:"refId"`
+			Type  string `json:"type"`
+		} `json:"changes"`
+		Repository struct {
+			Links struct {
+				Clone []struct {
+					Href string `json:"href"`
+				} `json:"clone"`
+			} `json:"links"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.Wrap(err, "invalid bitbucket push payload")
+	}
+
+	event := &pushEvent{}
+	for _, clone := range payload.Repository.Links.Clone {
+		event.repositoryURLs = append(event.repositoryURLs, clone.Href)
+	}
+	for _, change := range payload.Changes {
+		if change.Type == "DELETE" {
+			continue
+		}
+		event.refs = append(event.refs, change.RefID)
+	}
+	return event, nil
+}
+
+func validSignature(signature, prefix string, hashFunc func() hash.Hash, body, secret []byte) bool {
+	if !strings.HasPrefix(signature, prefix) {
+		return false
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(hashFunc, secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,61 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+type Status struct {
+	Leader bool `json:"leader"`
+	Synced bool `json:"synced"`
+}
+
+// Checker tracks whether the controller holds the leader lease and whether its informers have synced.
+type Checker struct {
+	mu     sync.RWMutex
+	status Status
+}
+
+func (c *Checker) SetLeader(leader bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.Leader = leader
+}
+
+func (c *Checker) SetSynced(synced bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.Synced = synced
+}
+
+func (c *Checker) Status() Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// Handler serves the status on /healthz, which always succeeds while the process is running, and
+// on /readyz, which succeeds once the informers have synced. Readiness does not depend on holding
+// the lease so that standby replicas do not block rolling updates.
+func (c *Checker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, http.StatusOK, c.Status())
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		status := c.Status()
+		if !status.Synced {
+			writeStatus(w, http.StatusServiceUnavailable, status)
+			return
+		}
+		writeStatus(w, http.StatusOK, status)
+	})
+	return mux
+}
+
+func writeStatus(w http.ResponseWriter, code int, status Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(status)
+}
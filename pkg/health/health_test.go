@@ -0,0 +1,63 @@
+package health_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pivotal/kpack/pkg/health"
+)
+
+func TestHealth(t *testing.T) {
+	spec.Run(t, "Health", testHealth)
+}
+
+func testHealth(t *testing.T, when spec.G, it spec.S) {
+	checker := &health.Checker{}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		checker.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	when("/healthz", func() {
+		it("reports the leader and sync state", func() {
+			checker.SetSynced(true)
+
+			response := get("/healthz")
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.JSONEq(t, `{"leader": false, "synced": true}`, response.Body.String())
+		})
+	})
+
+	when("/readyz", func() {
+		it("is not ready before the informers have synced", func() {
+			checker.SetLeader(true)
+
+			response := get("/readyz")
+			assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+			assert.JSONEq(t, `{"leader": true, "synced": false}`, response.Body.String())
+		})
+
+		it("is ready when synced without holding the lease", func() {
+			checker.SetSynced(true)
+
+			response := get("/readyz")
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.JSONEq(t, `{"leader": false, "synced": true}`, response.Body.String())
+		})
+
+		it("reports the leader state when ready", func() {
+			checker.SetSynced(true)
+			checker.SetLeader(true)
+
+			response := get("/readyz")
+			assert.Equal(t, http.StatusOK, response.Code)
+			assert.JSONEq(t, `{"leader": true, "synced": true}`, response.Body.String())
+		})
+	})
+}
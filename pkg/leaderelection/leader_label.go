@@ -0,0 +1,39 @@
+package leaderelection
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// LeaderLabel is set on the pod of the replica holding the lease so that services can select the leader.
+const LeaderLabel = "build.pivotal.io/leader"
+
+// LeaderLabeler adds and removes LeaderLabel on the pod this replica runs in.
+type LeaderLabeler struct {
+	Client    corev1client.PodsGetter
+	Namespace string
+	PodName   string
+}
+
+func (l *LeaderLabeler) Label(leader bool) error {
+	var value interface{}
+	if leader {
+		value = "true"
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				LeaderLabel: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = l.Client.Pods(l.Namespace).Patch(l.PodName, types.MergePatchType, patch)
+	return err
+}
@@ -0,0 +1,68 @@
+package leaderelection_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pivotal/kpack/pkg/leaderelection"
+)
+
+func TestLeaderLabeler(t *testing.T) {
+	spec.Run(t, "Leader Labeler", testLeaderLabeler)
+}
+
+func testLeaderLabeler(t *testing.T, when spec.G, it spec.S) {
+	const (
+		namespace = "kpack"
+		podName   = "kpack-controller-abc"
+	)
+
+	k8sClient := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app": "kpack-controller",
+			},
+		},
+	})
+
+	labeler := &leaderelection.LeaderLabeler{
+		Client:    k8sClient.CoreV1(),
+		Namespace: namespace,
+		PodName:   podName,
+	}
+
+	podLabels := func() map[string]string {
+		pod, err := k8sClient.CoreV1().Pods(namespace).Get(podName, metav1.GetOptions{})
+		require.NoError(t, err)
+		return pod.Labels
+	}
+
+	it("labels the pod while it is the leader", func() {
+		require.NoError(t, labeler.Label(true))
+
+		assert.Equal(t, map[string]string{
+			"app":                      "kpack-controller",
+			leaderelection.LeaderLabel: "true",
+		}, podLabels())
+
+		require.NoError(t, labeler.Label(false))
+
+		assert.Equal(t, map[string]string{
+			"app": "kpack-controller",
+		}, podLabels())
+	})
+
+	it("returns an error if the pod does not exist", func() {
+		labeler.PodName = "missing-pod"
+
+		assert.Error(t, labeler.Label(true))
+	})
+}
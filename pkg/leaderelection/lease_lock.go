@@ -0,0 +1,130 @@
+package leaderelection
+
+import (
+	"errors"
+
+	coordinationv1beta1 "k8s.io/api/coordination/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationclient "k8s.io/client-go/kubernetes/typed/coordination/v1beta1"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// LeaseLock is a resourcelock.Interface backed by a coordination.k8s.io Lease.
+type LeaseLock struct {
+	LeaseMeta  metav1.ObjectMeta
+	Client     coordinationclient.LeasesGetter
+	LockConfig resourcelock.ResourceLockConfig
+	lease      *coordinationv1beta1.Lease
+}
+
+func (l *LeaseLock) Get() (*resourcelock.LeaderElectionRecord, error) {
+	lease, err := l.Client.Leases(l.LeaseMeta.Namespace).Get(l.LeaseMeta.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	l.lease = lease
+	return leaseSpecToRecord(&lease.Spec), nil
+}
+
+func (l *LeaseLock) Create(record resourcelock.LeaderElectionRecord) error {
+	lease, err := l.Client.Leases(l.LeaseMeta.Namespace).Create(&coordinationv1beta1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      l.LeaseMeta.Name,
+			Namespace: l.LeaseMeta.Namespace,
+		},
+		Spec: recordToLeaseSpec(record),
+	})
+	if err != nil {
+		return err
+	}
+	l.lease = lease
+	return nil
+}
+
+func (l *LeaseLock) Update(record resourcelock.LeaderElectionRecord) error {
+	if l.lease == nil {
+		return errors.New("lease not initialized, call get or create first")
+	}
+
+	lease := l.lease.DeepCopy()
+	lease.Spec = recordToLeaseSpec(record)
+	lease, err := l.Client.Leases(l.LeaseMeta.Namespace).Update(lease)
+	if err != nil {
+		return err
+	}
+	l.lease = lease
+	return nil
+}
+
+func (l *LeaseLock) RecordEvent(s string) {
+	if l.LockConfig.EventRecorder == nil || l.lease == nil {
+		return
+	}
+	subject := &coordinationv1beta1.Lease{ObjectMeta: l.lease.ObjectMeta}
+	l.LockConfig.EventRecorder.Eventf(subject, corev1.EventTypeNormal, "LeaderElection", "%s %s", l.LockConfig.Identity, s)
+}
+
+func (l *LeaseLock) Describe() string {
+	return l.LeaseMeta.Namespace + "/" + l.LeaseMeta.Name
+}
+
+func (l *LeaseLock) Identity() string {
+	return l.LockConfig.Identity
+}
+
+// Release gives up the lease if it is still held by this replica so that another
+// replica can acquire it without waiting for it to expire.
+func (l *LeaseLock) Release() error {
+	record, err := l.Get()
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if record.HolderIdentity != l.Identity() {
+		return nil
+	}
+
+	now := metav1.Now()
+	return l.Update(resourcelock.LeaderElectionRecord{
+		LeaseDurationSeconds: 1,
+		AcquireTime:          now,
+		RenewTime:            now,
+		LeaderTransitions:    record.LeaderTransitions,
+	})
+}
+
+func leaseSpecToRecord(spec *coordinationv1beta1.LeaseSpec) *resourcelock.LeaderElectionRecord {
+	record := &resourcelock.LeaderElectionRecord{}
+	if spec.HolderIdentity != nil {
+		record.HolderIdentity = *spec.HolderIdentity
+	}
+	if spec.LeaseDurationSeconds != nil {
+		record.LeaseDurationSeconds = int(*spec.LeaseDurationSeconds)
+	}
+	if spec.LeaseTransitions != nil {
+		record.LeaderTransitions = int(*spec.LeaseTransitions)
+	}
+	if spec.AcquireTime != nil {
+		record.AcquireTime = metav1.Time{Time: spec.AcquireTime.Time}
+	}
+	if spec.RenewTime != nil {
+		record.RenewTime = metav1.Time{Time: spec.RenewTime.Time}
+	}
+	return record
+}
+
+func recordToLeaseSpec(record resourcelock.LeaderElectionRecord) coordinationv1beta1.LeaseSpec {
+	leaseDurationSeconds := int32(record.LeaseDurationSeconds)
+	leaseTransitions := int32(record.LeaderTransitions)
+	return coordinationv1beta1.LeaseSpec{
+		HolderIdentity:       &record.HolderIdentity,
+		LeaseDurationSeconds: &leaseDurationSeconds,
+		AcquireTime:          &metav1.MicroTime{Time: record.AcquireTime.Time},
+		RenewTime:            &metav1.MicroTime{Time: record.RenewTime.Time},
+		LeaseTransitions:     &leaseTransitions,
+	}
+}
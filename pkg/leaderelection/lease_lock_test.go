@@ -0,0 +1,131 @@
+package leaderelection_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/pivotal/kpack/pkg/leaderelection"
+)
+
+func TestLeaseLock(t *testing.T) {
+	spec.Run(t, "Lease Lock", testLeaseLock)
+}
+
+func testLeaseLock(t *testing.T, when spec.G, it spec.S) {
+	const (
+		namespace = "kpack"
+		leaseName = "kpack-controller"
+	)
+
+	k8sClient := fake.NewSimpleClientset()
+
+	newLock := func(identity string) *leaderelection.LeaseLock {
+		return &leaderelection.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      leaseName,
+				Namespace: namespace,
+			},
+			Client: k8sClient.CoordinationV1beta1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: identity,
+			},
+		}
+	}
+
+	lock := newLock("some-replica")
+
+	acquireTime := metav1.NewTime(time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC))
+
+	record := resourcelock.LeaderElectionRecord{
+		HolderIdentity:       "some-replica",
+		LeaseDurationSeconds: 15,
+		AcquireTime:          acquireTime,
+		RenewTime:            acquireTime,
+		LeaderTransitions:    2,
+	}
+
+	when("#Get", func() {
+		it("returns not found when the lease does not exist", func() {
+			_, err := lock.Get()
+			assert.True(t, k8serrors.IsNotFound(err))
+		})
+
+		it("returns the record stored in the lease", func() {
+			require.NoError(t, lock.Create(record))
+
+			stored, err := newLock("other-replica").Get()
+			require.NoError(t, err)
+			assert.Equal(t, record, *stored)
+		})
+	})
+
+	when("#Update", func() {
+		it("updates the lease", func() {
+			require.NoError(t, lock.Create(record))
+
+			otherLock := newLock("other-replica")
+			_, err := otherLock.Get()
+			require.NoError(t, err)
+
+			renewTime := metav1.NewTime(acquireTime.Add(time.Minute))
+			require.NoError(t, otherLock.Update(resourcelock.LeaderElectionRecord{
+				HolderIdentity:       "other-replica",
+				LeaseDurationSeconds: 15,
+				AcquireTime:          renewTime,
+				RenewTime:            renewTime,
+				LeaderTransitions:    3,
+			}))
+
+			stored, err := lock.Get()
+			require.NoError(t, err)
+			assert.Equal(t, "other-replica", stored.HolderIdentity)
+			assert.Equal(t, 3, stored.LeaderTransitions)
+		})
+
+		it("requires the lease to be fetched first", func() {
+			assert.Error(t, lock.Update(record))
+		})
+	})
+
+	when("#Release", func() {
+		it("clears the holder when the lease is held by this replica", func() {
+			require.NoError(t, lock.Create(record))
+
+			require.NoError(t, lock.Release())
+
+			stored, err := newLock("other-replica").Get()
+			require.NoError(t, err)
+			assert.Equal(t, "", stored.HolderIdentity)
+			assert.Equal(t, 1, stored.LeaseDurationSeconds)
+			assert.Equal(t, 2, stored.LeaderTransitions)
+		})
+
+		it("does not release a lease held by another replica", func() {
+			require.NoError(t, newLock("other-replica").Create(resourcelock.LeaderElectionRecord{
+				HolderIdentity:       "other-replica",
+				LeaseDurationSeconds: 15,
+				AcquireTime:          acquireTime,
+				RenewTime:            acquireTime,
+			}))
+
+			require.NoError(t, lock.Release())
+
+			stored, err := lock.Get()
+			require.NoError(t, err)
+			assert.Equal(t, "other-replica", stored.HolderIdentity)
+			assert.Equal(t, 15, stored.LeaseDurationSeconds)
+		})
+
+		it("does nothing when the lease does not exist", func() {
+			assert.NoError(t, lock.Release())
+		})
+	})
+}
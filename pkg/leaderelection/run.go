@@ -0,0 +1,75 @@
+package leaderelection
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"k8s.io/client-go/tools/leaderelection"
+)
+
+var ErrLeadershipLost = errors.New("leader election lost")
+
+type Config struct {
+	Lock          *LeaseLock
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// Run blocks until stop is closed and calls run while this replica holds the lease. The done
+// channel passed to run is closed when the lease is lost or stop is closed.
+//
+// On stop the lease is released once run has returned so another replica can take over
+// immediately. ErrLeadershipLost is returned if the lease could not be renewed.
+func Run(stop <-chan struct{}, config Config, run func(done <-chan struct{}) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	started := make(chan struct{})
+	result := make(chan error, 1)
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          config.Lock,
+		LeaseDuration: config.LeaseDuration,
+		RenewDeadline: config.RenewDeadline,
+		RetryPeriod:   config.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leading context.Context) {
+				close(started)
+				result <- run(leading.Done())
+				cancel()
+			},
+			OnStoppedLeading: func() {},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	elector.Run(ctx)
+	lost := ctx.Err() == nil
+	cancel()
+
+	select {
+	case <-started:
+		err = <-result
+	default:
+	}
+
+	if lost {
+		return ErrLeadershipLost
+	}
+
+	if releaseErr := config.Lock.Release(); releaseErr != nil && err == nil {
+		err = releaseErr
+	}
+	return err
+}
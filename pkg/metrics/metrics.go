@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+)
+
+const namespace = "kpack"
+
+const (
+	BuildSucceeded = "succeeded"
+	BuildFailed    = "failed"
+	BuildCanceled  = "canceled"
+	BuildTimedOut  = "timed_out"
+)
+
+var (
+	Registry = prometheus.NewRegistry()
+
+	buildsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "builds_total",
+		Help:      "Number of finished builds.",
+	}, []string{"namespace", "image", "reason", "outcome"})
+
+	buildDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "build_duration_seconds",
+		Help:      "Duration of finished builds from creation to completion.",
+		Buckets:   prometheus.ExponentialBuckets(15, 2, 10),
+	}, []string{"namespace", "image", "reason", "outcome"})
+
+	buildStepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "build_step_duration_seconds",
+		Help:      "Duration of the steps of finished builds.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"namespace", "image", "step"})
+
+	sourceResolutionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "source_resolution_duration_seconds",
+		Help:      "Duration of source resolutions.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"type"})
+
+	sourceResolutionFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_resolution_failures_total",
+		Help:      "Number of failed source resolutions.",
+	}, []string{"type"})
+
+	builderPollFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "builder_poll_failures_total",
+		Help:      "Number of failures to fetch the metadata of a builder image.",
+	}, []string{"kind", "namespace", "name"})
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		prometheus.NewGoCollector(),
+		buildsTotal,
+		buildDuration,
+		buildStepDuration,
+		sourceResolutionDuration,
+		sourceResolutionFailures,
+		builderPollFailures,
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// BuildFinished records the outcome, duration and step durations of a finished build.
+func BuildFinished(build *v1alpha1.Build) {
+	condition := build.Status.GetCondition(duckv1alpha1.ConditionSucceeded)
+	if condition == nil {
+		return
+	}
+
+	labels := prometheus.Labels{
+		"namespace": build.Namespace(),
+		"image":     build.Labels[v1alpha1.ImageLabel],
+		"reason":    build.Annotations[v1alpha1.BuildReasonAnnotation],
+		"outcome":   buildOutcome(condition),
+	}
+
+	finishedAt := condition.LastTransitionTime.Inner.Time
+	if finishedAt.IsZero() {
+		finishedAt = time.Now()
+	}
+
+	buildsTotal.With(labels).Inc()
+	buildDuration.With(labels).Observe(finishedAt.Sub(build.CreationTimestamp.Time).Seconds())
+
+	// StepsCompleted names the terminated step states in order
+	completed := 0
+	for _, state := range build.Status.StepStates {
+		if state.Terminated == nil || completed >= len(build.Status.StepsCompleted) {
+			continue
+		}
+
+		buildStepDuration.With(prometheus.Labels{
+			"namespace": build.Namespace(),
+			"image":     build.Labels[v1alpha1.ImageLabel],
+			"step":      build.Status.StepsCompleted[completed],
+		}).Observe(state.Terminated.FinishedAt.Sub(state.Terminated.StartedAt.Time).Seconds())
+		completed++
+	}
+}
+
+func buildOutcome(condition *duckv1alpha1.Condition) string {
+	switch {
+	case condition.Status == corev1.ConditionTrue:
+		return BuildSucceeded
+	case condition.Reason == v1alpha1.BuildCanceledReason:
+		return BuildCanceled
+	case condition.Reason == v1alpha1.BuildTimedOutReason:
+		return BuildTimedOut
+	default:
+		return BuildFailed
+	}
+}
+
+// SourceResolved records the duration of a source resolution and whether it failed.
+func SourceResolved(sourceType string, started time.Time, err error) {
+	sourceResolutionDuration.WithLabelValues(sourceType).Observe(time.Since(started).Seconds())
+	if err != nil {
+		sourceResolutionFailures.WithLabelValues(sourceType).Inc()
+	}
+}
+
+func BuilderPollFailed(kind, namespace, name string) {
+	builderPollFailures.WithLabelValues(kind, namespace, name).Inc()
+}
@@ -0,0 +1,174 @@
+package metrics_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pivotal/kpack/pkg/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	spec.Run(t, "Metrics", testMetrics)
+}
+
+func testMetrics(t *testing.T, when spec.G, it spec.S) {
+	scrape := func() string {
+		server := httptest.NewServer(metrics.Handler())
+		defer server.Close()
+
+		resp, err := server.Client().Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	when("#BuildFinished", func() {
+		created := time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC)
+
+		build := func(image string, condition duckv1alpha1.Condition) *v1alpha1.Build {
+			return &v1alpha1.Build{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              image + "-build-1",
+					Namespace:         "some-namespace",
+					CreationTimestamp: metav1.NewTime(created),
+					Labels: map[string]string{
+						v1alpha1.ImageLabel: image,
+					},
+					Annotations: map[string]string{
+						v1alpha1.BuildReasonAnnotation: "CONFIG",
+					},
+				},
+				Status: v1alpha1.BuildStatus{
+					Status: duckv1alpha1.Status{
+						Conditions: duckv1alpha1.Conditions{condition},
+					},
+					StepStates: []corev1.ContainerState{
+						{
+							Terminated: &corev1.ContainerStateTerminated{
+								StartedAt:  metav1.NewTime(created),
+								FinishedAt: metav1.NewTime(created.Add(10 * time.Second)),
+							},
+						},
+						{
+							Terminated: &corev1.ContainerStateTerminated{
+								StartedAt:  metav1.NewTime(created.Add(10 * time.Second)),
+								FinishedAt: metav1.NewTime(created.Add(100 * time.Second)),
+							},
+						},
+					},
+					StepsCompleted: []string{"prepare", "build"},
+				},
+			}
+		}
+
+		it("records the outcome and duration of a successful build", func() {
+			metrics.BuildFinished(build("success-image", duckv1alpha1.Condition{
+				Type:               duckv1alpha1.ConditionSucceeded,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(2 * time.Minute))},
+			}))
+
+			body := scrape()
+			assert.Contains(t, body, `kpack_builds_total{image="success-image",namespace="some-namespace",outcome="succeeded",reason="CONFIG"} 1`)
+			assert.Contains(t, body, `kpack_build_duration_seconds_sum{image="success-image",namespace="some-namespace",outcome="succeeded",reason="CONFIG"} 120`)
+			assert.Contains(t, body, `kpack_build_duration_seconds_count{image="success-image",namespace="some-namespace",outcome="succeeded",reason="CONFIG"} 1`)
+		})
+
+		it("records the duration of each completed step", func() {
+			metrics.BuildFinished(build("steps-image", duckv1alpha1.Condition{
+				Type:               duckv1alpha1.ConditionSucceeded,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(2 * time.Minute))},
+			}))
+
+			body := scrape()
+			assert.Contains(t, body, `kpack_build_step_duration_seconds_sum{image="steps-image",namespace="some-namespace",step="prepare"} 10`)
+			assert.Contains(t, body, `kpack_build_step_duration_seconds_sum{image="steps-image",namespace="some-namespace",step="build"} 90`)
+		})
+
+		it("records canceled, timed out and failed builds", func() {
+			metrics.BuildFinished(build("canceled-image", duckv1alpha1.Condition{
+				Type:               duckv1alpha1.ConditionSucceeded,
+				Status:             corev1.ConditionFalse,
+				Reason:             v1alpha1.BuildCanceledReason,
+				LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(time.Minute))},
+			}))
+			metrics.BuildFinished(build("timed-out-image", duckv1alpha1.Condition{
+				Type:               duckv1alpha1.ConditionSucceeded,
+				Status:             corev1.ConditionFalse,
+				Reason:             v1alpha1.BuildTimedOutReason,
+				LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(time.Minute))},
+			}))
+			metrics.BuildFinished(build("failed-image", duckv1alpha1.Condition{
+				Type:               duckv1alpha1.ConditionSucceeded,
+				Status:             corev1.ConditionFalse,
+				Reason:             "SomeReason",
+				LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(time.Minute))},
+			}))
+
+			body := scrape()
+			assert.Contains(t, body, `kpack_builds_total{image="canceled-image",namespace="some-namespace",outcome="canceled",reason="CONFIG"} 1`)
+			assert.Contains(t, body, `kpack_builds_total{image="timed-out-image",namespace="some-namespace",outcome="timed_out",reason="CONFIG"} 1`)
+			assert.Contains(t, body, `kpack_builds_total{image="failed-image",namespace="some-namespace",outcome="failed",reason="CONFIG"} 1`)
+		})
+	})
+
+	when("#SourceResolved", func() {
+		it("records the resolution duration and failures per source type", func() {
+			metrics.SourceResolved("git", time.Now(), nil)
+			metrics.SourceResolved("blob", time.Now(), errors.New("some error"))
+
+			body := scrape()
+			assert.Contains(t, body, `kpack_source_resolution_duration_seconds_count{type="git"} 1`)
+			assert.Contains(t, body, `kpack_source_resolution_duration_seconds_count{type="blob"} 1`)
+			assert.Contains(t, body, `kpack_source_resolution_failures_total{type="blob"} 1`)
+			assert.NotContains(t, body, `kpack_source_resolution_failures_total{type="git"}`)
+		})
+	})
+
+	when("#BuilderPollFailed", func() {
+		it("records failures per builder", func() {
+			metrics.BuilderPollFailed(v1alpha1.BuilderKind, "some-namespace", "some-builder")
+			metrics.BuilderPollFailed(v1alpha1.BuilderKind, "some-namespace", "some-builder")
+
+			assert.Contains(t, scrape(), `kpack_builder_poll_failures_total{kind="Builder",name="some-builder",namespace="some-namespace"} 2`)
+		})
+	})
+
+	when("#RegisterWorkqueueMetrics", func() {
+		it("records the depth and latency of named work queues", func() {
+			metrics.RegisterWorkqueueMetrics()
+
+			queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "SomeQueue")
+			defer queue.ShutDown()
+
+			queue.Add("some-namespace/some-key")
+			queue.Add("some-namespace/other-key")
+
+			item, _ := queue.Get()
+			queue.Done(item)
+
+			body := scrape()
+			assert.Contains(t, body, `kpack_workqueue_depth{name="SomeQueue"} 1`)
+			assert.Contains(t, body, `kpack_workqueue_adds_total{name="SomeQueue"} 2`)
+			assert.Contains(t, body, `kpack_workqueue_queue_duration_seconds_count{name="SomeQueue"} 1`)
+			assert.Contains(t, body, `kpack_workqueue_work_duration_seconds_count{name="SomeQueue"} 1`)
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package reconciler
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Backoff is a workqueue.RateLimiter that doubles the delay for each failure of an item. The base and maximum
+// delay are read on every call so a configuration change applies to items that are already backing off.
+type Backoff struct {
+	delays func() (base, max time.Duration)
+
+	mu       sync.Mutex
+	failures map[interface{}]int
+}
+
+func NewBackoff(delays func() (base, max time.Duration)) *Backoff {
+	return &Backoff{
+		delays:   delays,
+		failures: map[interface{}]int{},
+	}
+}
+
+func (b *Backoff) When(item interface{}) time.Duration {
+	b.mu.Lock()
+	exp := b.failures[item]
+	b.failures[item]++
+	b.mu.Unlock()
+
+	base, max := b.delays()
+	backoff := float64(base) * math.Pow(2, float64(exp))
+	if backoff > float64(max) {
+		return max
+	}
+	return time.Duration(backoff)
+}
+
+func (b *Backoff) Forget(item interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.failures, item)
+}
+
+func (b *Backoff) NumRequeues(item interface{}) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures[item]
+}
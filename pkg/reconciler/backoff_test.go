@@ -0,0 +1,61 @@
+package reconciler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pivotal/kpack/pkg/reconciler"
+)
+
+func TestBackoff(t *testing.T) {
+	spec.Run(t, "Backoff", testBackoff)
+}
+
+func testBackoff(t *testing.T, when spec.G, it spec.S) {
+	var (
+		base = time.Minute
+		max  = 5 * time.Minute
+	)
+
+	backoff := reconciler.NewBackoff(func() (time.Duration, time.Duration) {
+		return base, max
+	})
+
+	it("doubles the delay for each failure up to the maximum", func() {
+		var delays []time.Duration
+		for i := 0; i < 5; i++ {
+			delays = append(delays, backoff.When("some-key"))
+		}
+
+		assert.Equal(t, []time.Duration{
+			time.Minute,
+			2 * time.Minute,
+			4 * time.Minute,
+			5 * time.Minute,
+			5 * time.Minute,
+		}, delays)
+		assert.Equal(t, 5, backoff.NumRequeues("some-key"))
+	})
+
+	it("applies changed delays to keys that are already backing off", func() {
+		backoff.When("some-key")
+		backoff.When("some-key")
+
+		base = 10 * time.Minute
+		max = time.Hour
+
+		assert.Equal(t, 40*time.Minute, backoff.When("some-key"))
+	})
+
+	it("starts over once a key is forgotten", func() {
+		backoff.When("some-key")
+		backoff.When("some-key")
+		backoff.Forget("some-key")
+
+		assert.Equal(t, time.Minute, backoff.When("some-key"))
+		assert.Equal(t, time.Minute, backoff.When("other-key"))
+	})
+}
@@ -0,0 +1,25 @@
+package reconciler
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+)
+
+// OwningImage returns a reference to the Image controlling obj, or nil if obj is not controlled by an Image.
+// Events recorded on the reference show up when describing the Image.
+func OwningImage(obj metav1.Object) *corev1.ObjectReference {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || owner.Kind != "Image" || owner.APIVersion != v1alpha1.SchemeGroupVersion.String() {
+		return nil
+	}
+
+	return &corev1.ObjectReference{
+		APIVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       owner.Name,
+		UID:        owner.UID,
+	}
+}
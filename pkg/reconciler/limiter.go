@@ -0,0 +1,51 @@
+package reconciler
+
+import (
+	"context"
+	"sync"
+
+	"github.com/knative/pkg/controller"
+)
+
+// LimitedReconciler bounds the number of keys reconciled concurrently. The limit is read before
+// each reconcile so the number of workers can change while the controller is running.
+type LimitedReconciler struct {
+	reconciler controller.Reconciler
+	limit      func() int
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	active int
+}
+
+func Limit(reconciler controller.Reconciler, limit func() int) *LimitedReconciler {
+	l := &LimitedReconciler{
+		reconciler: reconciler,
+		limit:      limit,
+	}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *LimitedReconciler) Reconcile(ctx context.Context, key string) error {
+	l.mu.Lock()
+	for l.active >= l.limit() {
+		l.cond.Wait()
+	}
+	l.active++
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		l.active--
+		l.mu.Unlock()
+		l.cond.Broadcast()
+	}()
+
+	return l.reconciler.Reconcile(ctx, key)
+}
+
+// LimitChanged wakes waiting workers so that a raised limit applies immediately.
+func (l *LimitedReconciler) LimitChanged() {
+	l.cond.Broadcast()
+}
@@ -0,0 +1,91 @@
+package reconciler_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pivotal/kpack/pkg/reconciler"
+)
+
+func TestLimitedReconciler(t *testing.T) {
+	spec.Run(t, "Limited Reconciler", testLimitedReconciler)
+}
+
+type blockingReconciler struct {
+	release chan struct{}
+	active  int32
+	peak    int32
+}
+
+func (r *blockingReconciler) Reconcile(ctx context.Context, key string) error {
+	active := atomic.AddInt32(&r.active, 1)
+	for {
+		peak := atomic.LoadInt32(&r.peak)
+		if active <= peak || atomic.CompareAndSwapInt32(&r.peak, peak, active) {
+			break
+		}
+	}
+
+	<-r.release
+	atomic.AddInt32(&r.active, -1)
+	return nil
+}
+
+func testLimitedReconciler(t *testing.T, when spec.G, it spec.S) {
+	var (
+		inner = &blockingReconciler{release: make(chan struct{})}
+		limit = int32(2)
+	)
+
+	limited := reconciler.Limit(inner, func() int {
+		return int(atomic.LoadInt32(&limit))
+	})
+
+	reconcileAll := func(count int) *sync.WaitGroup {
+		wg := &sync.WaitGroup{}
+		wg.Add(count)
+		for i := 0; i < count; i++ {
+			go func() {
+				defer wg.Done()
+				_ = limited.Reconcile(context.Background(), "some-namespace/some-name")
+			}()
+		}
+		return wg
+	}
+
+	active := func() int32 {
+		return atomic.LoadInt32(&inner.active)
+	}
+
+	it("does not reconcile more keys than the limit at once", func() {
+		wg := reconcileAll(5)
+
+		assert.Eventually(t, func() bool { return active() == 2 }, time.Second, time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(2), active())
+
+		close(inner.release)
+		wg.Wait()
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(&inner.peak))
+	})
+
+	it("applies a raised limit to waiting workers", func() {
+		wg := reconcileAll(5)
+		assert.Eventually(t, func() bool { return active() == 2 }, time.Second, time.Millisecond)
+
+		atomic.StoreInt32(&limit, 4)
+		limited.LimitChanged()
+
+		assert.Eventually(t, func() bool { return active() == 4 }, time.Second, time.Millisecond)
+
+		close(inner.release)
+		wg.Wait()
+	})
+}
@@ -4,19 +4,20 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
+	"github.com/pivotal/kpack/pkg/config"
 )
 
 type Options struct {
-	Logger *zap.SugaredLogger
+	Logger   *zap.SugaredLogger
+	Recorder record.EventRecorder
 
-	Client                  versioned.Interface
-	ResyncPeriod            time.Duration
-	SourcePollingFrequency  time.Duration
-	BuilderPollingFrequency time.Duration
+	Client      versioned.Interface
+	ConfigStore *config.Store
 }
 
 func (o Options) TrackerResyncPeriod() time.Duration {
-	return o.ResyncPeriod * 3
+	return o.ConfigStore.Load().ResyncPeriod * 3
 }
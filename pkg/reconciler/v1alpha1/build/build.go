@@ -2,10 +2,15 @@ package build
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/knative/pkg/apis"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/controller"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
@@ -14,12 +19,14 @@ import (
 	k8sclient "k8s.io/client-go/kubernetes"
 	v1Listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
 	v1alpha1informer "github.com/pivotal/kpack/pkg/client/informers/externalversions/build/v1alpha1"
 	v1alpha1lister "github.com/pivotal/kpack/pkg/client/listers/build/v1alpha1"
 	"github.com/pivotal/kpack/pkg/cnb"
+	"github.com/pivotal/kpack/pkg/metrics"
 	"github.com/pivotal/kpack/pkg/reconciler"
 	"github.com/pivotal/kpack/pkg/registry"
 )
@@ -27,8 +34,11 @@ import (
 const (
 	ReconcilerName = "Builds"
 	Kind           = "Build"
+
+	podDeadlineExceededReason = "DeadlineExceeded"
 )
 
+//go:generate counterfeiter . MetadataRetriever
 type MetadataRetriever interface {
 	GetBuiltImage(repoName registry.ImageRef) (cnb.BuiltImage, error)
 }
@@ -37,18 +47,28 @@ type PodGenerator interface {
 	Generate(*v1alpha1.Build) (*corev1.Pod, error)
 }
 
+//go:generate counterfeiter . Enqueuer
+type Enqueuer interface {
+	EnqueueAfter(*v1alpha1.Build, time.Duration) error
+}
+
 func NewController(opt reconciler.Options, k8sClient k8sclient.Interface, informer v1alpha1informer.BuildInformer, podInformer corev1Informers.PodInformer, metadataRetriever MetadataRetriever, podGenerator PodGenerator) *controller.Impl {
 	c := &Reconciler{
 		Client:            opt.Client,
 		K8sClient:         k8sClient,
-		MetadataRetriever: metadataRetriever,
 		Lister:            informer.Lister(),
 		PodLister:         podInformer.Lister(),
+		MetadataRetriever: metadataRetriever,
 		PodGenerator:      podGenerator,
+		Recorder:          opt.Recorder,
 	}
 
 	impl := controller.NewImpl(c, opt.Logger, ReconcilerName)
 
+	c.Enqueuer = &workQueueEnqueuer{
+		enqueueAfter: impl.EnqueueAfter,
+	}
+
 	informer.Informer().AddEventHandler(reconciler.Handler(impl.Enqueue))
 
 	podInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
@@ -66,6 +86,8 @@ type Reconciler struct {
 	K8sClient         k8sclient.Interface
 	PodLister         v1Listers.PodLister
 	PodGenerator      PodGenerator
+	Enqueuer          Enqueuer
+	Recorder          record.EventRecorder
 }
 
 func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
@@ -86,28 +108,62 @@ func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
 		return nil
 	}
 
+	if build.CancelRequested() {
+		return c.cancelBuild(build)
+	}
+
 	pod, err := c.reconcileBuildPod(build)
 	if err != nil {
 		return err
 	}
 
-	if build.MetadataReady(pod) {
-		image, err := c.MetadataRetriever.GetBuiltImage(build)
+	if timedOut(build, pod) {
+		err := c.deleteRunningPod(pod)
 		if err != nil {
 			return err
 		}
 
-		build.Status.BuildMetadata = buildMetadataFromBuiltImage(image)
-		build.Status.LatestImage = image.Identifier
+		build.Status.Conditions = timedOutConditions(build)
+	} else {
+		if build.MetadataReady(pod) {
+			image, err := builtImageFromPod(pod)
+			if err != nil {
+				return err
+			}
+
+			// The build pod could not reach the registry or its result could not be read, retrieve the
+			// image here so that a registry outage retries the reconcile rather than failing the build.
+			if image.Identifier == "" {
+				image, err = c.MetadataRetriever.GetBuiltImage(build)
+				if err != nil {
+					return err
+				}
+			}
+
+			build.Status.BuildMetadata = buildMetadataFromBuiltImage(image)
+			build.Status.LatestImage = image.Identifier
+			build.Status.Stack = v1alpha1.BuildStack{
+				RunImage: image.Stack.RunImage,
+				ID:       image.Stack.ID,
+			}
+		}
+
+		build.Status.Conditions = conditionForPod(pod)
 	}
 
 	build.Status.PodName = pod.Name
 	build.Status.StepStates = stepStates(pod)
 	build.Status.StepsCompleted = stepCompleted(pod)
-	build.Status.Conditions = conditionForPod(pod)
 
 	build.Status.ObservedGeneration = build.Generation
 
+	if build.HasTimeout() && build.IsRunning() {
+		err := c.Enqueuer.EnqueueAfter(build, time.Until(build.Deadline()))
+		if err != nil {
+			return err
+		}
+	}
+
 	return c.updateStatus(build)
 }
 
@@ -126,6 +182,66 @@ func (c *Reconciler) reconcileBuildPod(build *v1alpha1.Build) (*corev1.Pod, erro
 	return pod, nil
 }
 
+func (c *Reconciler) cancelBuild(build *v1alpha1.Build) error {
+	pod, err := c.PodLister.Pods(build.Namespace()).Get(build.PodName())
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	} else if err == nil {
+		err := c.deleteRunningPod(pod)
+		if err != nil {
+			return err
+		}
+	}
+
+	build.Status.Conditions = duckv1alpha1.Conditions{
+		{
+			Type:               duckv1alpha1.ConditionSucceeded,
+			Status:             corev1.ConditionFalse,
+			Reason:             v1alpha1.BuildCanceledReason,
+			Message:            "Build was canceled",
+			LastTransitionTime: apis.VolatileTime{Inner: metav1.Now()},
+		},
+	}
+	build.Status.ObservedGeneration = build.Generation
+
+	return c.updateStatus(build)
+}
+
+func (c *Reconciler) deleteRunningPod(pod *corev1.Pod) error {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return nil
+	}
+
+	err := c.K8sClient.CoreV1().Pods(pod.Namespace).Delete(pod.Name, &metav1.DeleteOptions{})
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func timedOut(build *v1alpha1.Build, pod *corev1.Pod) bool {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return false
+	case corev1.PodFailed:
+		return pod.Status.Reason == podDeadlineExceededReason
+	default:
+		return build.TimedOut(time.Now())
+	}
+}
+
+func timedOutConditions(build *v1alpha1.Build) duckv1alpha1.Conditions {
+	return duckv1alpha1.Conditions{
+		{
+			Type:               duckv1alpha1.ConditionSucceeded,
+			Status:             corev1.ConditionFalse,
+			Reason:             v1alpha1.BuildTimedOutReason,
+			Message:            fmt.Sprintf("Build did not finish within its timeout of %s", build.Spec.Timeout.Duration),
+			LastTransitionTime: apis.VolatileTime{Inner: metav1.Now()},
+		},
+	}
+}
+
 func conditionForPod(pod *corev1.Pod) duckv1alpha1.Conditions {
 	switch pod.Status.Phase {
 	case corev1.PodSucceeded:
@@ -137,10 +253,13 @@ func conditionForPod(pod *corev1.Pod) duckv1alpha1.Conditions {
 			},
 		}
 	case corev1.PodFailed:
+		reason, message := failureForPod(pod)
 		return duckv1alpha1.Conditions{
 			{
 				Type:               duckv1alpha1.ConditionSucceeded,
 				Status:             corev1.ConditionFalse,
+				Reason:             reason,
+				Message:            message,
 				LastTransitionTime: apis.VolatileTime{Inner: metav1.Now()},
 			},
 		}
@@ -155,6 +274,35 @@ func conditionForPod(pod *corev1.Pod) duckv1alpha1.Conditions {
 
 }
 
+func failureForPod(pod *corev1.Pod) (string, string) {
+	var statuses []corev1.ContainerStatus
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, s := range statuses {
+		if s.State.Terminated == nil || s.State.Terminated.ExitCode == 0 {
+			continue
+		}
+
+		message := fmt.Sprintf("Step %s failed with exit code %d", s.Name, s.State.Terminated.ExitCode)
+		if terminationMessage := strings.TrimSpace(s.State.Terminated.Message); terminationMessage != "" {
+			message = fmt.Sprintf("%s: %s", message, terminationMessage)
+		}
+		return stepFailedReason(s.Name), message
+	}
+
+	return pod.Status.Reason, pod.Status.Message
+}
+
+// stepFailedReason turns a step name such as "creds-init" into "CredsInitFailed".
+func stepFailedReason(step string) string {
+	var reason strings.Builder
+	for _, part := range strings.Split(step, "-") {
+		reason.WriteString(strings.Title(part))
+	}
+	reason.WriteString("Failed")
+	return reason.String()
+}
+
 func stepStates(pod *corev1.Pod) []corev1.ContainerState {
 	states := make([]corev1.ContainerState, 0, len(pod.Status.InitContainerStatuses))
 	for _, s := range pod.Status.InitContainerStatuses {
@@ -184,7 +332,56 @@ func (c *Reconciler) updateStatus(desired *v1alpha1.Build) error {
 	}
 
 	_, err = c.Client.BuildV1alpha1().Builds(desired.Namespace()).UpdateStatus(desired)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if desired.Finished() {
+		c.buildFinished(desired)
+	}
+	return nil
+}
+
+func (c *Reconciler) buildFinished(build *v1alpha1.Build) {
+	metrics.BuildFinished(build)
+
+	image := reconciler.OwningImage(build)
+
+	condition := build.Status.GetCondition(duckv1alpha1.ConditionSucceeded)
+	if condition.IsTrue() {
+		c.Recorder.Event(build, corev1.EventTypeNormal, "Succeeded", "Build succeeded")
+		if image != nil {
+			c.Recorder.Eventf(image, corev1.EventTypeNormal, "Succeeded", "Build %s succeeded", build.Name)
+		}
+		return
+	}
+
+	reason := condition.Reason
+	if reason == "" {
+		reason = "Failed"
+	}
+	c.Recorder.Event(build, corev1.EventTypeWarning, reason, condition.Message)
+	if image != nil {
+		c.Recorder.Eventf(image, corev1.EventTypeWarning, reason, "Build %s failed: %s", build.Name, condition.Message)
+	}
+}
+
+func builtImageFromPod(pod *corev1.Pod) (cnb.BuiltImage, error) {
+	for _, s := range pod.Status.ContainerStatuses {
+		if s.Name != v1alpha1.CompletionContainerName || s.State.Terminated == nil {
+			continue
+		}
+
+		// A result over the termination message limit is truncated by the kubelet. The image has been
+		// pushed, so an unreadable result is reported without an identifier to read it from the registry.
+		var image cnb.BuiltImage
+		if err := json.Unmarshal([]byte(s.State.Terminated.Message), &image); err != nil {
+			return cnb.BuiltImage{}, nil
+		}
+		return image, nil
+	}
+
+	return cnb.BuiltImage{}, errors.Errorf("build pod %s did not report a build result", pod.Name)
 }
 
 func buildMetadataFromBuiltImage(image cnb.BuiltImage) []v1alpha1.BuildpackMetadata {
@@ -1,10 +1,11 @@
 package build_test
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
-	lcyclemd "github.com/buildpack/lifecycle/metadata"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/controller"
 	"github.com/knative/pkg/kmeta"
@@ -29,8 +30,6 @@ import (
 	"github.com/pivotal/kpack/pkg/reconciler/v1alpha1/build/buildfakes"
 )
 
-//go:generate counterfeiter . MetadataRetriever
-
 func TestBuildReconciler(t *testing.T) {
 	spec.Run(t, "Build Reconciler", testBuildReconciler)
 }
@@ -47,6 +46,7 @@ func testBuildReconciler(t *testing.T, when spec.G, it spec.S) {
 
 	var (
 		fakeMetadataRetriever = &buildfakes.FakeMetadataRetriever{}
+		fakeEnqueuer          = &buildfakes.FakeEnqueuer{}
 	)
 
 	podGenerator := &testPodGenerator{}
@@ -69,6 +69,8 @@ func testBuildReconciler(t *testing.T, when spec.G, it spec.S) {
 				PodLister:         listers.GetPodLister(),
 				MetadataRetriever: fakeMetadataRetriever,
 				PodGenerator:      podGenerator,
+				Enqueuer:          fakeEnqueuer,
+				Recorder:          eventRecorder,
 			}
 
 			rtesting.PrependGenerateNameReactor(&fakeClient.Fake)
@@ -372,17 +374,33 @@ func testBuildReconciler(t *testing.T, when spec.G, it spec.S) {
 			builtImage := cnb.BuiltImage{
 				Identifier:  identifier,
 				CompletedAt: time.Now(),
-				BuildpackMetadata: []lcyclemd.BuildpackMetadata{{
+				BuildpackMetadata: []cnb.BuildpackMetadata{{
 					ID:      "io.buildpack.executed",
 					Version: "1.1",
 				}},
+				Stack: cnb.BuiltImageStack{
+					RunImage: "index.docker.io/cloudfoundry/run@sha256:a1b2c3",
+					ID:       "io.buildpacks.stacks.bionic",
+				},
 			}
-			fakeMetadataRetriever.GetBuiltImageReturns(builtImage, nil)
+			buildResult, err := json.Marshal(builtImage)
+			require.NoError(t, err)
 
-			it("sets the build status to Succeeded", func() {
+			completionStatus := corev1.ContainerStatus{
+				Name: "completion",
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 0,
+						Message:  string(buildResult),
+					},
+				},
+			}
+
+			it("sets the build status to Succeeded with the result reported by the pod", func() {
 				pod, err := podGenerator.Generate(build)
 				require.NoError(t, err)
 				pod.Status.Phase = corev1.PodSucceeded
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{completionStatus}
 				pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
 					{
 						Name: "step-1",
@@ -437,6 +455,10 @@ func testBuildReconciler(t *testing.T, when spec.G, it spec.S) {
 										Version: "1.1",
 									}},
 									LatestImage: identifier,
+									Stack: v1alpha1.BuildStack{
+										RunImage: "index.docker.io/cloudfoundry/run@sha256:a1b2c3",
+										ID:       "io.buildpacks.stacks.bionic",
+									},
 									StepStates: []corev1.ContainerState{
 										{
 											Terminated: &corev1.ContainerStateTerminated{
@@ -463,12 +485,194 @@ func testBuildReconciler(t *testing.T, when spec.G, it spec.S) {
 							},
 						},
 					},
+					WantEvents: []string{
+						"Normal Succeeded Build succeeded",
+					},
+				})
+
+				assert.Equal(t, 0, fakeMetadataRetriever.GetBuiltImageCallCount())
+			})
+
+			it("returns an error if the pod did not report a build result", func() {
+				pod, err := podGenerator.Generate(build)
+				require.NoError(t, err)
+				pod.Status.Phase = corev1.PodSucceeded
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						builder,
+						build,
+						pod,
+					},
+					WantErr: true,
+				})
+			})
+
+			when("the pod could not retrieve the built image from the registry", func() {
+				reportedImage := builtImage
+				reportedImage.Identifier = ""
+				reportedResult, err := json.Marshal(reportedImage)
+				require.NoError(t, err)
+
+				stepStatus := corev1.ContainerStatus{
+					Name: "step-1",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode:    0,
+							Reason:      "Terminated",
+							ContainerID: "container.ID",
+						},
+					},
+				}
+
+				completionStatus := corev1.ContainerStatus{
+					Name: "completion",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 0,
+							Message:  string(reportedResult),
+						},
+					},
+				}
+
+				it("retrieves the built image from the registry", func() {
+					fakeMetadataRetriever.GetBuiltImageReturns(builtImage, nil)
+
+					pod, err := podGenerator.Generate(build)
+					require.NoError(t, err)
+					pod.Status.Phase = corev1.PodSucceeded
+					pod.Status.ContainerStatuses = []corev1.ContainerStatus{completionStatus}
+					pod.Status.InitContainerStatuses = []corev1.ContainerStatus{stepStatus}
+
+					rt.Test(rtesting.TableRow{
+						Key: key,
+						Objects: []runtime.Object{
+							builder,
+							build,
+							pod,
+						},
+						WantErr: false,
+						WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+							{
+								Object: &v1alpha1.Build{
+									ObjectMeta: build.ObjectMeta,
+									Spec:       build.Spec,
+									Status: v1alpha1.BuildStatus{
+										Status: duckv1alpha1.Status{
+											ObservedGeneration: originalGeneration,
+											Conditions: duckv1alpha1.Conditions{
+												{
+													Type:   duckv1alpha1.ConditionSucceeded,
+													Status: corev1.ConditionTrue,
+												},
+											},
+										},
+										PodName: "build-name-build-pod",
+										BuildMetadata: v1alpha1.BuildpackMetadataList{{
+											ID:      "io.buildpack.executed",
+											Version: "1.1",
+										}},
+										LatestImage: identifier,
+										Stack: v1alpha1.BuildStack{
+											RunImage: "index.docker.io/cloudfoundry/run@sha256:a1b2c3",
+											ID:       "io.buildpacks.stacks.bionic",
+										},
+										StepStates:     []corev1.ContainerState{stepStatus.State},
+										StepsCompleted: []string{"step-1"},
+									},
+								},
+							},
+						},
+						WantEvents: []string{
+							"Normal Succeeded Build succeeded",
+						},
+					})
+
+					assert.Equal(t, 1, fakeMetadataRetriever.GetBuiltImageCallCount())
+				})
+
+				it("retrieves the built image from the registry when the build result is truncated", func() {
+					fakeMetadataRetriever.GetBuiltImageReturns(builtImage, nil)
+
+					truncatedStatus := *completionStatus.DeepCopy()
+					truncatedStatus.State.Terminated.Message = string(buildResult)[:len(buildResult)/2]
+
+					pod, err := podGenerator.Generate(build)
+					require.NoError(t, err)
+					pod.Status.Phase = corev1.PodSucceeded
+					pod.Status.ContainerStatuses = []corev1.ContainerStatus{truncatedStatus}
+					pod.Status.InitContainerStatuses = []corev1.ContainerStatus{stepStatus}
+
+					rt.Test(rtesting.TableRow{
+						Key: key,
+						Objects: []runtime.Object{
+							builder,
+							build,
+							pod,
+						},
+						WantErr: false,
+						WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+							{
+								Object: &v1alpha1.Build{
+									ObjectMeta: build.ObjectMeta,
+									Spec:       build.Spec,
+									Status: v1alpha1.BuildStatus{
+										Status: duckv1alpha1.Status{
+											ObservedGeneration: originalGeneration,
+											Conditions: duckv1alpha1.Conditions{
+												{
+													Type:   duckv1alpha1.ConditionSucceeded,
+													Status: corev1.ConditionTrue,
+												},
+											},
+										},
+										PodName: "build-name-build-pod",
+										BuildMetadata: v1alpha1.BuildpackMetadataList{{
+											ID:      "io.buildpack.executed",
+											Version: "1.1",
+										}},
+										LatestImage: identifier,
+										Stack: v1alpha1.BuildStack{
+											RunImage: "index.docker.io/cloudfoundry/run@sha256:a1b2c3",
+											ID:       "io.buildpacks.stacks.bionic",
+										},
+										StepStates:     []corev1.ContainerState{stepStatus.State},
+										StepsCompleted: []string{"step-1"},
+									},
+								},
+							},
+						},
+						WantEvents: []string{
+							"Normal Succeeded Build succeeded",
+						},
+					})
+
+					assert.Equal(t, 1, fakeMetadataRetriever.GetBuiltImageCallCount())
 				})
 
-				assert.Equal(t, fakeMetadataRetriever.GetBuiltImageCallCount(), 1)
+				it("retries the reconcile without failing the build when the registry is unavailable", func() {
+					fakeMetadataRetriever.GetBuiltImageReturns(cnb.BuiltImage{}, errors.New("registry unavailable"))
+
+					pod, err := podGenerator.Generate(build)
+					require.NoError(t, err)
+					pod.Status.Phase = corev1.PodSucceeded
+					pod.Status.ContainerStatuses = []corev1.ContainerStatus{completionStatus}
+					pod.Status.InitContainerStatuses = []corev1.ContainerStatus{stepStatus}
+
+					rt.Test(rtesting.TableRow{
+						Key: key,
+						Objects: []runtime.Object{
+							builder,
+							build,
+							pod,
+						},
+						WantErr: true,
+					})
+				})
 			})
 
-			it("does not fetch metadata if already retrieved", func() {
+			it("does not read the build result if already reported", func() {
 				pod, err := podGenerator.Generate(build)
 				require.NoError(t, err)
 				pod.Status.Phase = corev1.PodSucceeded
@@ -550,7 +754,6 @@ func testBuildReconciler(t *testing.T, when spec.G, it spec.S) {
 					},
 					WantErr: false,
 				})
-				assert.Equal(t, fakeMetadataRetriever.GetBuiltImageCallCount(), 0)
 			})
 
 			it("does not recreate pods if build has finished", func() {
@@ -654,8 +857,10 @@ func testBuildReconciler(t *testing.T, when spec.G, it spec.S) {
 										ObservedGeneration: originalGeneration,
 										Conditions: duckv1alpha1.Conditions{
 											{
-												Type:   duckv1alpha1.ConditionSucceeded,
-												Status: corev1.ConditionFalse,
+												Type:    duckv1alpha1.ConditionSucceeded,
+												Status:  corev1.ConditionFalse,
+												Reason:  "Step1Failed",
+												Message: "Step step-1 failed with exit code 1: Errors",
 											},
 										},
 									},
@@ -683,6 +888,103 @@ func testBuildReconciler(t *testing.T, when spec.G, it spec.S) {
 							},
 						},
 					},
+					WantEvents: []string{
+						"Warning Step1Failed Step step-1 failed with exit code 1: Errors",
+					},
+				})
+			})
+
+			it("reports the pod failure reason when no step failed", func() {
+				pod, err := podGenerator.Generate(build)
+				require.NoError(t, err)
+				pod.Status.Phase = corev1.PodFailed
+				pod.Status.Reason = "Evicted"
+				pod.Status.Message = "The node was low on resource: ephemeral-storage."
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						builder,
+						build,
+						pod,
+					},
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Build{
+								ObjectMeta: build.ObjectMeta,
+								Spec:       build.Spec,
+								Status: v1alpha1.BuildStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:    duckv1alpha1.ConditionSucceeded,
+												Status:  corev1.ConditionFalse,
+												Reason:  "Evicted",
+												Message: "The node was low on resource: ephemeral-storage.",
+											},
+										},
+									},
+									PodName: "build-name-build-pod",
+								},
+							},
+						},
+					},
+					WantEvents: []string{
+						"Warning Evicted The node was low on resource: ephemeral-storage.",
+					},
+				})
+			})
+
+			it("also records the failure on the owning image", func() {
+				build := build.DeepCopy()
+				build.OwnerReferences = []metav1.OwnerReference{
+					*kmeta.NewControllerRef(&v1alpha1.Image{
+						ObjectMeta: v1.ObjectMeta{Name: "some-image", Namespace: namespace},
+					}),
+				}
+
+				pod, err := podGenerator.Generate(build)
+				require.NoError(t, err)
+				pod.Status.Phase = corev1.PodFailed
+				pod.Status.Reason = "Evicted"
+				pod.Status.Message = "The node was low on resource: ephemeral-storage."
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						builder,
+						build,
+						pod,
+					},
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Build{
+								ObjectMeta: build.ObjectMeta,
+								Spec:       build.Spec,
+								Status: v1alpha1.BuildStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:    duckv1alpha1.ConditionSucceeded,
+												Status:  corev1.ConditionFalse,
+												Reason:  "Evicted",
+												Message: "The node was low on resource: ephemeral-storage.",
+											},
+										},
+									},
+									PodName: "build-name-build-pod",
+								},
+							},
+						},
+					},
+					WantEvents: []string{
+						"Warning Evicted The node was low on resource: ephemeral-storage.",
+						"Warning Evicted Build build-name failed: The node was low on resource: ephemeral-storage.",
+					},
 				})
 			})
 
@@ -732,6 +1034,257 @@ func testBuildReconciler(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("build is canceled", func() {
+			it.Before(func() {
+				build.Annotations = map[string]string{
+					v1alpha1.BuildCancelAnnotation: "true",
+				}
+			})
+
+			it("deletes the running pod and marks the build as canceled", func() {
+				pod, err := podGenerator.Generate(build)
+				require.NoError(t, err)
+				pod.Status.Phase = corev1.PodRunning
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						builder,
+						build,
+						pod,
+					},
+					WantErr: false,
+					WantDeletes: []clientgotesting.DeleteActionImpl{
+						{
+							ActionImpl: clientgotesting.ActionImpl{
+								Namespace: namespace,
+							},
+							Name: pod.Name,
+						},
+					},
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Build{
+								ObjectMeta: build.ObjectMeta,
+								Spec:       build.Spec,
+								Status: v1alpha1.BuildStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:    duckv1alpha1.ConditionSucceeded,
+												Status:  corev1.ConditionFalse,
+												Reason:  v1alpha1.BuildCanceledReason,
+												Message: "Build was canceled",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					WantEvents: []string{
+						"Warning BuildCanceled Build was canceled",
+					},
+				})
+			})
+
+			it("does not create a pod for a canceled build", func() {
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						builder,
+						build,
+					},
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Build{
+								ObjectMeta: build.ObjectMeta,
+								Spec:       build.Spec,
+								Status: v1alpha1.BuildStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:    duckv1alpha1.ConditionSucceeded,
+												Status:  corev1.ConditionFalse,
+												Reason:  v1alpha1.BuildCanceledReason,
+												Message: "Build was canceled",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					WantEvents: []string{
+						"Warning BuildCanceled Build was canceled",
+					},
+				})
+			})
+
+			it("does not cancel a build that has already finished", func() {
+				build.Status.Conditions = duckv1alpha1.Conditions{
+					{
+						Type:   duckv1alpha1.ConditionSucceeded,
+						Status: corev1.ConditionTrue,
+					},
+				}
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						builder,
+						build,
+					},
+					WantErr: false,
+				})
+			})
+		})
+
+		when("build has a timeout", func() {
+			it.Before(func() {
+				build.Spec.Timeout = &metav1.Duration{Duration: time.Hour}
+			})
+
+			it("requeues the build to be reconciled at its deadline", func() {
+				build.CreationTimestamp = metav1.Now()
+				pod, err := podGenerator.Generate(build)
+				require.NoError(t, err)
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						builder,
+						build,
+						pod,
+					},
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Build{
+								ObjectMeta: build.ObjectMeta,
+								Spec:       build.Spec,
+								Status: v1alpha1.BuildStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:   duckv1alpha1.ConditionSucceeded,
+												Status: corev1.ConditionUnknown,
+											},
+										},
+									},
+									PodName: "build-name-build-pod",
+								},
+							},
+						},
+					},
+				})
+
+				require.Equal(t, 1, fakeEnqueuer.EnqueueAfterCallCount())
+				enqueuedBuild, after := fakeEnqueuer.EnqueueAfterArgsForCall(0)
+				assert.Equal(t, buildName, enqueuedBuild.Name)
+				assert.True(t, after <= time.Hour)
+				assert.True(t, after > 59*time.Minute)
+			})
+
+			it("deletes the pod and fails the build once the deadline has passed", func() {
+				build.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+				pod, err := podGenerator.Generate(build)
+				require.NoError(t, err)
+				pod.Status.Phase = corev1.PodPending
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						builder,
+						build,
+						pod,
+					},
+					WantErr: false,
+					WantDeletes: []clientgotesting.DeleteActionImpl{
+						{
+							ActionImpl: clientgotesting.ActionImpl{
+								Namespace: namespace,
+							},
+							Name: pod.Name,
+						},
+					},
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Build{
+								ObjectMeta: build.ObjectMeta,
+								Spec:       build.Spec,
+								Status: v1alpha1.BuildStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:    duckv1alpha1.ConditionSucceeded,
+												Status:  corev1.ConditionFalse,
+												Reason:  v1alpha1.BuildTimedOutReason,
+												Message: "Build did not finish within its timeout of 1h0m0s",
+											},
+										},
+									},
+									PodName: "build-name-build-pod",
+								},
+							},
+						},
+					},
+					WantEvents: []string{
+						"Warning BuildTimedOut Build did not finish within its timeout of 1h0m0s",
+					},
+				})
+
+				assert.Equal(t, 0, fakeEnqueuer.EnqueueAfterCallCount())
+			})
+
+			it("fails the build when the pod exceeded its active deadline", func() {
+				build.CreationTimestamp = metav1.Now()
+				pod, err := podGenerator.Generate(build)
+				require.NoError(t, err)
+				pod.Status.Phase = corev1.PodFailed
+				pod.Status.Reason = "DeadlineExceeded"
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						builder,
+						build,
+						pod,
+					},
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Build{
+								ObjectMeta: build.ObjectMeta,
+								Spec:       build.Spec,
+								Status: v1alpha1.BuildStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:    duckv1alpha1.ConditionSucceeded,
+												Status:  corev1.ConditionFalse,
+												Reason:  v1alpha1.BuildTimedOutReason,
+												Message: "Build did not finish within its timeout of 1h0m0s",
+											},
+										},
+									},
+									PodName: "build-name-build-pod",
+								},
+							},
+						},
+					},
+					WantEvents: []string{
+						"Warning BuildTimedOut Build did not finish within its timeout of 1h0m0s",
+					},
+				})
+			})
+		})
 	})
 }
 
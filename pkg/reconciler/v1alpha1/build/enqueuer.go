@@ -0,0 +1,16 @@
+package build
+
+import (
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+)
+
+type workQueueEnqueuer struct {
+	enqueueAfter func(obj interface{}, after time.Duration)
+}
+
+func (e *workQueueEnqueuer) EnqueueAfter(build *v1alpha1.Build, after time.Duration) error {
+	e.enqueueAfter(build, after)
+	return nil
+}
@@ -0,0 +1,29 @@
+package build
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+)
+
+func TestEnqueueAfter(t *testing.T) {
+	build := &v1alpha1.Build{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "name",
+		},
+	}
+
+	enqueuer := &workQueueEnqueuer{
+		enqueueAfter: func(obj interface{}, after time.Duration) {
+			require.Equal(t, build, obj)
+			require.Equal(t, after, 5*time.Minute)
+		},
+	}
+
+	err := enqueuer.EnqueueAfter(build, 5*time.Minute)
+	require.NoError(t, err)
+}
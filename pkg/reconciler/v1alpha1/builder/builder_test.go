@@ -45,6 +45,7 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 			r := &builder.Reconciler{
 				Client:            fakeClient,
 				BuilderLister:     listers.GetBuilderLister(),
+				Recorder:          eventRecorder,
 				MetadataRetriever: fakeMetadataRetriever,
 				Enqueuer:          fakeEnqueuer,
 			}
@@ -58,6 +59,7 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 		key                     = "some-namespace/builder-name"
 		imageName               = "some/builder"
 		builderIdentifier       = "some/builder@sha256:resolved-builder-digest"
+		runImage                = "some/run@sha256:resolved-run-digest"
 		initalGeneration  int64 = 1
 	)
 
@@ -83,6 +85,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 					},
 				},
 				Identifier: builderIdentifier,
+				Stack: cnb.BuilderStack{
+					ID:              "io.buildpacks.stacks.bionic",
+					BuildImage:      "some/build:base",
+					RunImage:        runImage,
+					RunImageMirrors: []string{"some.mirror/run:base"},
+				},
+				LifecycleVersion: "0.3.0",
+				Order: []cnb.BuilderGroupMetadata{
+					{
+						Buildpacks: []cnb.BuilderGroupBuildpack{
+							{ID: "buildpack.version", Version: "version"},
+						},
+					},
+				},
 			}, nil)
 
 			it("saves metadata to the status", func() {
@@ -97,6 +113,10 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 									Type:   duckv1alpha1.ConditionReady,
 									Status: corev1.ConditionTrue,
 								},
+								{
+									Type:   v1alpha1.ConditionUpToDate,
+									Status: corev1.ConditionTrue,
+								},
 							},
 						},
 						BuilderMetadata: []v1alpha1.BuildpackMetadata{
@@ -106,6 +126,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 							},
 						},
 						LatestImage: builderIdentifier,
+						Stack: v1alpha1.BuilderStackStatus{
+							ID:              "io.buildpacks.stacks.bionic",
+							BuildImage:      "some/build:base",
+							RunImage:        runImage,
+							RunImageMirrors: []string{"some.mirror/run:base"},
+						},
+						LifecycleVersion: "0.3.0",
+						Order: []v1alpha1.BuilderOrderGroup{
+							{
+								Group: []v1alpha1.BuilderOrderBuildpack{
+									{ID: "buildpack.version", Version: "version"},
+								},
+							},
+						},
 					},
 				}
 				rt.Test(rtesting.TableRow{
@@ -141,6 +175,10 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 												Type:   duckv1alpha1.ConditionReady,
 												Status: corev1.ConditionTrue,
 											},
+											{
+												Type:   v1alpha1.ConditionUpToDate,
+												Status: corev1.ConditionTrue,
+											},
 										},
 									},
 									BuilderMetadata: []v1alpha1.BuildpackMetadata{
@@ -150,6 +188,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 										},
 									},
 									LatestImage: builderIdentifier,
+									Stack: v1alpha1.BuilderStackStatus{
+										ID:              "io.buildpacks.stacks.bionic",
+										BuildImage:      "some/build:base",
+										RunImage:        runImage,
+										RunImageMirrors: []string{"some.mirror/run:base"},
+									},
+									LifecycleVersion: "0.3.0",
+									Order: []v1alpha1.BuilderOrderGroup{
+										{
+											Group: []v1alpha1.BuilderOrderBuildpack{
+												{ID: "buildpack.version", Version: "version"},
+											},
+										},
+									},
 								},
 							},
 						},
@@ -177,6 +229,10 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 												Type:   duckv1alpha1.ConditionReady,
 												Status: corev1.ConditionTrue,
 											},
+											{
+												Type:   v1alpha1.ConditionUpToDate,
+												Status: corev1.ConditionTrue,
+											},
 										},
 									},
 									BuilderMetadata: []v1alpha1.BuildpackMetadata{
@@ -186,6 +242,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 										},
 									},
 									LatestImage: builderIdentifier,
+									Stack: v1alpha1.BuilderStackStatus{
+										ID:              "io.buildpacks.stacks.bionic",
+										BuildImage:      "some/build:base",
+										RunImage:        runImage,
+										RunImageMirrors: []string{"some.mirror/run:base"},
+									},
+									LifecycleVersion: "0.3.0",
+									Order: []v1alpha1.BuilderOrderGroup{
+										{
+											Group: []v1alpha1.BuilderOrderBuildpack{
+												{ID: "buildpack.version", Version: "version"},
+											},
+										},
+									},
 								},
 							},
 						},
@@ -213,6 +283,10 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 												Type:   duckv1alpha1.ConditionReady,
 												Status: corev1.ConditionTrue,
 											},
+											{
+												Type:   v1alpha1.ConditionUpToDate,
+												Status: corev1.ConditionTrue,
+											},
 										},
 									},
 									BuilderMetadata: []v1alpha1.BuildpackMetadata{
@@ -222,6 +296,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 										},
 									},
 									LatestImage: builderIdentifier,
+									Stack: v1alpha1.BuilderStackStatus{
+										ID:              "io.buildpacks.stacks.bionic",
+										BuildImage:      "some/build:base",
+										RunImage:        runImage,
+										RunImageMirrors: []string{"some.mirror/run:base"},
+									},
+									LifecycleVersion: "0.3.0",
+									Order: []v1alpha1.BuilderOrderGroup{
+										{
+											Group: []v1alpha1.BuilderOrderBuildpack{
+												{ID: "buildpack.version", Version: "version"},
+											},
+										},
+									},
 								},
 							},
 						},
@@ -240,6 +328,10 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 								Type:   duckv1alpha1.ConditionReady,
 								Status: corev1.ConditionTrue,
 							},
+							{
+								Type:   v1alpha1.ConditionUpToDate,
+								Status: corev1.ConditionTrue,
+							},
 						},
 					},
 					BuilderMetadata: []v1alpha1.BuildpackMetadata{
@@ -249,6 +341,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 						},
 					},
 					LatestImage: builderIdentifier,
+					Stack: v1alpha1.BuilderStackStatus{
+						ID:              "io.buildpacks.stacks.bionic",
+						BuildImage:      "some/build:base",
+						RunImage:        runImage,
+						RunImageMirrors: []string{"some.mirror/run:base"},
+					},
+					LifecycleVersion: "0.3.0",
+					Order: []v1alpha1.BuilderOrderGroup{
+						{
+							Group: []v1alpha1.BuilderOrderBuildpack{
+								{ID: "buildpack.version", Version: "version"},
+							},
+						},
+					},
 				}
 
 				rt.Test(rtesting.TableRow{
@@ -257,6 +363,81 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 					WantErr: false,
 				})
 			})
+
+			it("records events when the builder image and buildpacks change", func() {
+				builder.Status = v1alpha1.BuilderStatus{
+					Status: duckv1alpha1.Status{
+						ObservedGeneration: builder.Generation,
+						Conditions: duckv1alpha1.Conditions{
+							{
+								Type:   duckv1alpha1.ConditionReady,
+								Status: corev1.ConditionTrue,
+							},
+						},
+					},
+					BuilderMetadata: []v1alpha1.BuildpackMetadata{
+						{
+							ID:      "buildpack.version",
+							Version: "previous-version",
+						},
+					},
+					LatestImage: "some/builder@sha256:previous-builder-digest",
+				}
+
+				rt.Test(rtesting.TableRow{
+					Key:     key,
+					Objects: []runtime.Object{builder},
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Builder{
+								ObjectMeta: builder.ObjectMeta,
+								Spec:       builder.Spec,
+								Status: v1alpha1.BuilderStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: 1,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:   duckv1alpha1.ConditionReady,
+												Status: corev1.ConditionTrue,
+											},
+											{
+												Type:   v1alpha1.ConditionUpToDate,
+												Status: corev1.ConditionTrue,
+											},
+										},
+									},
+									BuilderMetadata: []v1alpha1.BuildpackMetadata{
+										{
+											ID:      "buildpack.version",
+											Version: "version",
+										},
+									},
+									LatestImage: builderIdentifier,
+									Stack: v1alpha1.BuilderStackStatus{
+										ID:              "io.buildpacks.stacks.bionic",
+										BuildImage:      "some/build:base",
+										RunImage:        runImage,
+										RunImageMirrors: []string{"some.mirror/run:base"},
+									},
+									LifecycleVersion: "0.3.0",
+									Order: []v1alpha1.BuilderOrderGroup{
+										{
+											Group: []v1alpha1.BuilderOrderBuildpack{
+												{ID: "buildpack.version", Version: "version"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					WantEvents: []string{
+						"Normal ImageChanged Builder image changed to " + builderIdentifier,
+						"Normal BuildpacksChanged Builder buildpacks changed to buildpack.version@version",
+					},
+				})
+			})
 		})
 
 		when("metadata is not available", func() {
@@ -281,6 +462,12 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 												Status:  corev1.ConditionFalse,
 												Message: "unavailable metadata",
 											},
+											{
+												Type:    v1alpha1.ConditionUpToDate,
+												Status:  corev1.ConditionFalse,
+												Reason:  v1alpha1.BuilderRefreshFailed,
+												Message: "unavailable metadata",
+											},
 										},
 									},
 								},
@@ -291,6 +478,69 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 
 				assert.Equal(t, fakeEnqueuer.EnqueueCallCount(), 1)
 			})
+
+			it("keeps the last resolved builder image when the builder spec has not changed", func() {
+				builder.Status = v1alpha1.BuilderStatus{
+					Status: duckv1alpha1.Status{
+						ObservedGeneration: builder.Generation,
+						Conditions: duckv1alpha1.Conditions{
+							{
+								Type:   duckv1alpha1.ConditionReady,
+								Status: corev1.ConditionTrue,
+							},
+							{
+								Type:   v1alpha1.ConditionUpToDate,
+								Status: corev1.ConditionTrue,
+							},
+						},
+					},
+					BuilderMetadata: []v1alpha1.BuildpackMetadata{
+						{
+							ID:      "buildpack.version",
+							Version: "version",
+						},
+					},
+					LatestImage: builderIdentifier,
+				}
+
+				rt.Test(rtesting.TableRow{
+					Key:     key,
+					Objects: []runtime.Object{builder},
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Builder{
+								ObjectMeta: builder.ObjectMeta,
+								Spec:       builder.Spec,
+								Status: v1alpha1.BuilderStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: 1,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:   duckv1alpha1.ConditionReady,
+												Status: corev1.ConditionTrue,
+											},
+											{
+												Type:    v1alpha1.ConditionUpToDate,
+												Status:  corev1.ConditionFalse,
+												Reason:  v1alpha1.BuilderRefreshFailed,
+												Message: "unavailable metadata",
+											},
+										},
+									},
+									BuilderMetadata: []v1alpha1.BuildpackMetadata{
+										{
+											ID:      "buildpack.version",
+											Version: "version",
+										},
+									},
+									LatestImage: builderIdentifier,
+								},
+							},
+						},
+					},
+				})
+			})
 		})
 
 		it("does not return error on nonexistent builder", func() {
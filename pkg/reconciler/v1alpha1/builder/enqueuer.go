@@ -3,15 +3,30 @@ package builder
 import (
 	"time"
 
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 )
 
 type workQueueEnqueuer struct {
 	enqueueAfter func(obj interface{}, after time.Duration)
-	delay        time.Duration
+	delay        func() time.Duration
+	backoff      workqueue.RateLimiter
 }
 
 func (e *workQueueEnqueuer) Enqueue(builder *v1alpha1.Builder) error {
-	e.enqueueAfter(builder, 1*time.Minute)
+	key, err := cache.MetaNamespaceKeyFunc(builder)
+	if err != nil {
+		return err
+	}
+
+	if !builder.Status.RefreshFailed() {
+		e.backoff.Forget(key)
+		e.enqueueAfter(builder, e.delay())
+		return nil
+	}
+
+	e.enqueueAfter(builder, e.backoff.When(key))
 	return nil
 }
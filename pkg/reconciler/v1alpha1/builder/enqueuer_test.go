@@ -4,8 +4,11 @@ import (
 	"testing"
 	"time"
 
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 )
@@ -18,13 +21,64 @@ func TestEnqueueAfter(t *testing.T) {
 	}
 
 	enqueuer := &workQueueEnqueuer{
-		delay: time.Minute,
+		delay: func() time.Duration {
+			return 5 * time.Minute
+		},
+		backoff: workqueue.NewItemExponentialFailureRateLimiter(5*time.Minute, 30*time.Minute),
 		enqueueAfter: func(obj interface{}, after time.Duration) {
 			require.Equal(t, builder, obj)
-			require.Equal(t, after, time.Minute)
+			require.Equal(t, after, 5*time.Minute)
 		},
 	}
 
 	err := enqueuer.Enqueue(builder)
 	require.NoError(t, err)
 }
+
+func TestEnqueueAfterBacksOffOnRefreshFailures(t *testing.T) {
+	builder := &v1alpha1.Builder{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "name",
+		},
+		Status: v1alpha1.BuilderStatus{
+			Status: duckv1alpha1.Status{
+				Conditions: duckv1alpha1.Conditions{
+					{
+						Type:   v1alpha1.ConditionUpToDate,
+						Status: corev1.ConditionFalse,
+					},
+				},
+			},
+		},
+	}
+
+	var delays []time.Duration
+	enqueuer := &workQueueEnqueuer{
+		delay: func() time.Duration {
+			return 5 * time.Minute
+		},
+		backoff: workqueue.NewItemExponentialFailureRateLimiter(5*time.Minute, 30*time.Minute),
+		enqueueAfter: func(obj interface{}, after time.Duration) {
+			delays = append(delays, after)
+		},
+	}
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, enqueuer.Enqueue(builder))
+	}
+
+	builder.Status.Conditions[0].Status = corev1.ConditionTrue
+	require.NoError(t, enqueuer.Enqueue(builder))
+
+	builder.Status.Conditions[0].Status = corev1.ConditionFalse
+	require.NoError(t, enqueuer.Enqueue(builder))
+
+	require.Equal(t, []time.Duration{
+		5 * time.Minute,
+		10 * time.Minute,
+		20 * time.Minute,
+		30 * time.Minute,
+		5 * time.Minute,
+		5 * time.Minute,
+	}, delays)
+}
@@ -2,6 +2,8 @@ package clusterbuilder
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/controller"
@@ -9,12 +11,14 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
 	v1alpha1informers "github.com/pivotal/kpack/pkg/client/informers/externalversions/build/v1alpha1"
 	v1alpha1Listers "github.com/pivotal/kpack/pkg/client/listers/build/v1alpha1"
 	"github.com/pivotal/kpack/pkg/cnb"
+	"github.com/pivotal/kpack/pkg/metrics"
 	"github.com/pivotal/kpack/pkg/reconciler"
 	"github.com/pivotal/kpack/pkg/registry"
 )
@@ -32,6 +36,7 @@ type MetadataRetriever interface {
 func NewController(opt reconciler.Options, clusterBuilderInformer v1alpha1informers.ClusterBuilderInformer, metadataRetriever MetadataRetriever) *controller.Impl {
 	c := &Reconciler{
 		Client:               opt.Client,
+		Recorder:             opt.Recorder,
 		MetadataRetriever:    metadataRetriever,
 		ClusterBuilderLister: clusterBuilderInformer.Lister(),
 	}
@@ -40,7 +45,13 @@ func NewController(opt reconciler.Options, clusterBuilderInformer v1alpha1inform
 
 	c.Enqueuer = &workQueueEnqueuer{
 		enqueueAfter: impl.EnqueueAfter,
-		delay:        opt.BuilderPollingFrequency,
+		delay: func() time.Duration {
+			return opt.ConfigStore.Load().BuilderPollingFrequency
+		},
+		backoff: reconciler.NewBackoff(func() (time.Duration, time.Duration) {
+			cfg := opt.ConfigStore.Load()
+			return cfg.BuilderPollingFrequency, cfg.BuilderPollingBackoff
+		}),
 	}
 
 	clusterBuilderInformer.Informer().AddEventHandler(reconciler.Handler(impl.Enqueue))
@@ -55,6 +66,7 @@ type Enqueuer interface {
 
 type Reconciler struct {
 	Client               versioned.Interface
+	Recorder             record.EventRecorder
 	MetadataRetriever    MetadataRetriever
 	Enqueuer             Enqueuer
 	ClusterBuilderLister v1alpha1Listers.ClusterBuilderLister
@@ -74,6 +86,7 @@ func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
 	}
 	builder = builder.DeepCopy()
 
+	previous := builder.Status
 	builder = c.reconcileClusterBuilderStatus(builder)
 
 	err = c.updateClusterBuilderStatus(builder)
@@ -81,6 +94,8 @@ func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
 		return err
 	}
 
+	c.recordChanges(builder, previous)
+
 	if builder.Spec.UpdatePolicy != v1alpha1.External {
 		err = c.Enqueuer.Enqueue(builder)
 		if err != nil {
@@ -107,19 +122,8 @@ func (c *Reconciler) updateClusterBuilderStatus(desired *v1alpha1.ClusterBuilder
 func (c *Reconciler) reconcileClusterBuilderStatus(builder *v1alpha1.ClusterBuilder) *v1alpha1.ClusterBuilder {
 	builderImage, err := c.MetadataRetriever.GetBuilderImage(builder)
 	if err != nil {
-		builder.Status = v1alpha1.BuilderStatus{
-			Status: duckv1alpha1.Status{
-				ObservedGeneration: builder.Generation,
-				Conditions: duckv1alpha1.Conditions{
-					{
-						Type:    duckv1alpha1.ConditionReady,
-						Status:  corev1.ConditionFalse,
-						Message: err.Error(),
-					},
-				},
-			},
-		}
-		return builder
+		metrics.BuilderPollFailed(v1alpha1.ClusterBuilderKind, "", builder.Name)
+		return refreshFailed(builder, err)
 	}
 
 	builder.Status = v1alpha1.BuilderStatus{
@@ -130,15 +134,86 @@ func (c *Reconciler) reconcileClusterBuilderStatus(builder *v1alpha1.ClusterBuil
 					Type:   duckv1alpha1.ConditionReady,
 					Status: corev1.ConditionTrue,
 				},
+				{
+					Type:   v1alpha1.ConditionUpToDate,
+					Status: corev1.ConditionTrue,
+				},
 			},
 		},
 		BuilderMetadata: transform(builderImage.BuilderBuildpackMetadata),
 		LatestImage:     builderImage.Identifier,
+		Stack: v1alpha1.BuilderStackStatus{
+			ID:              builderImage.Stack.ID,
+			BuildImage:      builderImage.Stack.BuildImage,
+			RunImage:        builderImage.Stack.RunImage,
+			RunImageMirrors: builderImage.Stack.RunImageMirrors,
+		},
+		LifecycleVersion: builderImage.LifecycleVersion,
+		Order:            transformOrder(builderImage.Order),
 	}
 
 	return builder
 }
 
+func (c *Reconciler) recordChanges(builder *v1alpha1.ClusterBuilder, previous v1alpha1.BuilderStatus) {
+	if previous.LatestImage == "" || builder.Status.LatestImage == "" {
+		return
+	}
+
+	if builder.Status.LatestImage != previous.LatestImage {
+		c.Recorder.Eventf(builder, corev1.EventTypeNormal, "ImageChanged", "Builder image changed to %s", builder.Status.LatestImage)
+	}
+
+	if !equality.Semantic.DeepEqual(builder.Status.BuilderMetadata, previous.BuilderMetadata) {
+		c.Recorder.Eventf(builder, corev1.EventTypeNormal, "BuildpacksChanged", "Builder buildpacks changed to %s", buildpacks(builder.Status.BuilderMetadata))
+	}
+}
+
+func buildpacks(metadata v1alpha1.BuildpackMetadataList) string {
+	ids := make([]string, 0, len(metadata))
+	for _, bp := range metadata {
+		ids = append(ids, bp.ID+"@"+bp.Version)
+	}
+	return strings.Join(ids, ", ")
+}
+
+// refreshFailed keeps serving the last resolved builder image when the builder spec has not
+// changed since it was resolved, so images using the builder stay ready.
+func refreshFailed(builder *v1alpha1.ClusterBuilder, err error) *v1alpha1.ClusterBuilder {
+	upToDate := duckv1alpha1.Condition{
+		Type:    v1alpha1.ConditionUpToDate,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1alpha1.BuilderRefreshFailed,
+		Message: err.Error(),
+	}
+
+	if builder.Status.LatestImage != "" && builder.Status.ObservedGeneration == builder.Generation {
+		builder.Status.Conditions = duckv1alpha1.Conditions{
+			{
+				Type:   duckv1alpha1.ConditionReady,
+				Status: corev1.ConditionTrue,
+			},
+			upToDate,
+		}
+		return builder
+	}
+
+	builder.Status = v1alpha1.BuilderStatus{
+		Status: duckv1alpha1.Status{
+			ObservedGeneration: builder.Generation,
+			Conditions: duckv1alpha1.Conditions{
+				{
+					Type:    duckv1alpha1.ConditionReady,
+					Status:  corev1.ConditionFalse,
+					Message: err.Error(),
+				},
+				upToDate,
+			},
+		},
+	}
+	return builder
+}
+
 func transform(in cnb.BuilderMetadata) v1alpha1.BuildpackMetadataList {
 	out := make(v1alpha1.BuildpackMetadataList, 0, len(in))
 
@@ -151,3 +226,21 @@ func transform(in cnb.BuilderMetadata) v1alpha1.BuildpackMetadataList {
 
 	return out
 }
+
+func transformOrder(in []cnb.BuilderGroupMetadata) []v1alpha1.BuilderOrderGroup {
+	out := make([]v1alpha1.BuilderOrderGroup, 0, len(in))
+
+	for _, g := range in {
+		group := make([]v1alpha1.BuilderOrderBuildpack, 0, len(g.Buildpacks))
+		for _, bp := range g.Buildpacks {
+			group = append(group, v1alpha1.BuilderOrderBuildpack{
+				ID:       bp.ID,
+				Version:  bp.Version,
+				Optional: bp.Optional,
+			})
+		}
+		out = append(out, v1alpha1.BuilderOrderGroup{Group: group})
+	}
+
+	return out
+}
@@ -45,6 +45,7 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 			r := &clusterbuilder.Reconciler{
 				Client:               fakeClient,
 				ClusterBuilderLister: listers.GetClusterBuilderLister(),
+				Recorder:             eventRecorder,
 				MetadataRetriever:    fakeMetadataRetriever,
 				Enqueuer:             fakeEnqueuer,
 			}
@@ -58,6 +59,7 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 		clusterBuilderKey              = "cluster-builder-name"
 		clusterImageName               = "some/cluster-builder"
 		clusterBuilderIdentifier       = "some/cluster-builder@sha256:resolved-builder-digest"
+		runImage                       = "some/run@sha256:resolved-run-digest"
 		initalGeneration         int64 = 1
 	)
 
@@ -82,6 +84,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 						},
 					},
 					Identifier: clusterBuilderIdentifier,
+					Stack: cnb.BuilderStack{
+						ID:              "io.buildpacks.stacks.bionic",
+						BuildImage:      "some/build:base",
+						RunImage:        runImage,
+						RunImageMirrors: []string{"some.mirror/run:base"},
+					},
+					LifecycleVersion: "0.3.0",
+					Order: []cnb.BuilderGroupMetadata{
+						{
+							Buildpacks: []cnb.BuilderGroupBuildpack{
+								{ID: "buildpack.version", Version: "version"},
+							},
+						},
+					},
 				}, nil)
 
 				it("saves metadata to the status", func() {
@@ -96,6 +112,10 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 										Type:   duckv1alpha1.ConditionReady,
 										Status: corev1.ConditionTrue,
 									},
+									{
+										Type:   v1alpha1.ConditionUpToDate,
+										Status: corev1.ConditionTrue,
+									},
 								},
 							},
 							BuilderMetadata: []v1alpha1.BuildpackMetadata{
@@ -105,6 +125,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 								},
 							},
 							LatestImage: clusterBuilderIdentifier,
+							Stack: v1alpha1.BuilderStackStatus{
+								ID:              "io.buildpacks.stacks.bionic",
+								BuildImage:      "some/build:base",
+								RunImage:        runImage,
+								RunImageMirrors: []string{"some.mirror/run:base"},
+							},
+							LifecycleVersion: "0.3.0",
+							Order: []v1alpha1.BuilderOrderGroup{
+								{
+									Group: []v1alpha1.BuilderOrderBuildpack{
+										{ID: "buildpack.version", Version: "version"},
+									},
+								},
+							},
 						},
 					}
 					rt.Test(rtesting.TableRow{
@@ -140,6 +174,10 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 													Type:   duckv1alpha1.ConditionReady,
 													Status: corev1.ConditionTrue,
 												},
+												{
+													Type:   v1alpha1.ConditionUpToDate,
+													Status: corev1.ConditionTrue,
+												},
 											},
 										},
 										BuilderMetadata: []v1alpha1.BuildpackMetadata{
@@ -149,6 +187,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 											},
 										},
 										LatestImage: clusterBuilderIdentifier,
+										Stack: v1alpha1.BuilderStackStatus{
+											ID:              "io.buildpacks.stacks.bionic",
+											BuildImage:      "some/build:base",
+											RunImage:        runImage,
+											RunImageMirrors: []string{"some.mirror/run:base"},
+										},
+										LifecycleVersion: "0.3.0",
+										Order: []v1alpha1.BuilderOrderGroup{
+											{
+												Group: []v1alpha1.BuilderOrderBuildpack{
+													{ID: "buildpack.version", Version: "version"},
+												},
+											},
+										},
 									},
 								},
 							},
@@ -176,6 +228,10 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 													Type:   duckv1alpha1.ConditionReady,
 													Status: corev1.ConditionTrue,
 												},
+												{
+													Type:   v1alpha1.ConditionUpToDate,
+													Status: corev1.ConditionTrue,
+												},
 											},
 										},
 										BuilderMetadata: []v1alpha1.BuildpackMetadata{
@@ -185,6 +241,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 											},
 										},
 										LatestImage: clusterBuilderIdentifier,
+										Stack: v1alpha1.BuilderStackStatus{
+											ID:              "io.buildpacks.stacks.bionic",
+											BuildImage:      "some/build:base",
+											RunImage:        runImage,
+											RunImageMirrors: []string{"some.mirror/run:base"},
+										},
+										LifecycleVersion: "0.3.0",
+										Order: []v1alpha1.BuilderOrderGroup{
+											{
+												Group: []v1alpha1.BuilderOrderBuildpack{
+													{ID: "buildpack.version", Version: "version"},
+												},
+											},
+										},
 									},
 								},
 							},
@@ -212,6 +282,10 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 													Type:   duckv1alpha1.ConditionReady,
 													Status: corev1.ConditionTrue,
 												},
+												{
+													Type:   v1alpha1.ConditionUpToDate,
+													Status: corev1.ConditionTrue,
+												},
 											},
 										},
 										BuilderMetadata: []v1alpha1.BuildpackMetadata{
@@ -221,6 +295,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 											},
 										},
 										LatestImage: clusterBuilderIdentifier,
+										Stack: v1alpha1.BuilderStackStatus{
+											ID:              "io.buildpacks.stacks.bionic",
+											BuildImage:      "some/build:base",
+											RunImage:        runImage,
+											RunImageMirrors: []string{"some.mirror/run:base"},
+										},
+										LifecycleVersion: "0.3.0",
+										Order: []v1alpha1.BuilderOrderGroup{
+											{
+												Group: []v1alpha1.BuilderOrderBuildpack{
+													{ID: "buildpack.version", Version: "version"},
+												},
+											},
+										},
 									},
 								},
 							},
@@ -239,6 +327,10 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 									Type:   duckv1alpha1.ConditionReady,
 									Status: corev1.ConditionTrue,
 								},
+								{
+									Type:   v1alpha1.ConditionUpToDate,
+									Status: corev1.ConditionTrue,
+								},
 							},
 						},
 						BuilderMetadata: []v1alpha1.BuildpackMetadata{
@@ -248,6 +340,20 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 							},
 						},
 						LatestImage: clusterBuilderIdentifier,
+						Stack: v1alpha1.BuilderStackStatus{
+							ID:              "io.buildpacks.stacks.bionic",
+							BuildImage:      "some/build:base",
+							RunImage:        runImage,
+							RunImageMirrors: []string{"some.mirror/run:base"},
+						},
+						LifecycleVersion: "0.3.0",
+						Order: []v1alpha1.BuilderOrderGroup{
+							{
+								Group: []v1alpha1.BuilderOrderBuildpack{
+									{ID: "buildpack.version", Version: "version"},
+								},
+							},
+						},
 					}
 
 					rt.Test(rtesting.TableRow{
@@ -256,6 +362,81 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 						WantErr: false,
 					})
 				})
+
+				it("records events when the builder image and buildpacks change", func() {
+					clusterBuilder.Status = v1alpha1.BuilderStatus{
+						Status: duckv1alpha1.Status{
+							ObservedGeneration: clusterBuilder.Generation,
+							Conditions: duckv1alpha1.Conditions{
+								{
+									Type:   duckv1alpha1.ConditionReady,
+									Status: corev1.ConditionTrue,
+								},
+							},
+						},
+						BuilderMetadata: []v1alpha1.BuildpackMetadata{
+							{
+								ID:      "buildpack.version",
+								Version: "previous-version",
+							},
+						},
+						LatestImage: "some/cluster-builder@sha256:previous-builder-digest",
+					}
+
+					rt.Test(rtesting.TableRow{
+						Key:     key,
+						Objects: []runtime.Object{clusterBuilder},
+						WantErr: false,
+						WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+							{
+								Object: &v1alpha1.ClusterBuilder{
+									ObjectMeta: clusterBuilder.ObjectMeta,
+									Spec:       clusterBuilder.Spec,
+									Status: v1alpha1.BuilderStatus{
+										Status: duckv1alpha1.Status{
+											ObservedGeneration: 1,
+											Conditions: duckv1alpha1.Conditions{
+												{
+													Type:   duckv1alpha1.ConditionReady,
+													Status: corev1.ConditionTrue,
+												},
+												{
+													Type:   v1alpha1.ConditionUpToDate,
+													Status: corev1.ConditionTrue,
+												},
+											},
+										},
+										BuilderMetadata: []v1alpha1.BuildpackMetadata{
+											{
+												ID:      "buildpack.version",
+												Version: "version",
+											},
+										},
+										LatestImage: clusterBuilderIdentifier,
+										Stack: v1alpha1.BuilderStackStatus{
+											ID:              "io.buildpacks.stacks.bionic",
+											BuildImage:      "some/build:base",
+											RunImage:        runImage,
+											RunImageMirrors: []string{"some.mirror/run:base"},
+										},
+										LifecycleVersion: "0.3.0",
+										Order: []v1alpha1.BuilderOrderGroup{
+											{
+												Group: []v1alpha1.BuilderOrderBuildpack{
+													{ID: "buildpack.version", Version: "version"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						WantEvents: []string{
+							"Normal ImageChanged Builder image changed to " + clusterBuilderIdentifier,
+							"Normal BuildpacksChanged Builder buildpacks changed to buildpack.version@version",
+						},
+					})
+				})
 			})
 
 			when("metadata is not available", func() {
@@ -280,6 +461,12 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 													Status:  corev1.ConditionFalse,
 													Message: "unavailable metadata",
 												},
+												{
+													Type:    v1alpha1.ConditionUpToDate,
+													Status:  corev1.ConditionFalse,
+													Reason:  v1alpha1.BuilderRefreshFailed,
+													Message: "unavailable metadata",
+												},
 											},
 										},
 									},
@@ -290,6 +477,69 @@ func testBuilderReconciler(t *testing.T, when spec.G, it spec.S) {
 
 					assert.Equal(t, fakeEnqueuer.EnqueueCallCount(), 1)
 				})
+
+				it("keeps the last resolved builder image when the builder spec has not changed", func() {
+					clusterBuilder.Status = v1alpha1.BuilderStatus{
+						Status: duckv1alpha1.Status{
+							ObservedGeneration: clusterBuilder.Generation,
+							Conditions: duckv1alpha1.Conditions{
+								{
+									Type:   duckv1alpha1.ConditionReady,
+									Status: corev1.ConditionTrue,
+								},
+								{
+									Type:   v1alpha1.ConditionUpToDate,
+									Status: corev1.ConditionTrue,
+								},
+							},
+						},
+						BuilderMetadata: []v1alpha1.BuildpackMetadata{
+							{
+								ID:      "buildpack.version",
+								Version: "version",
+							},
+						},
+						LatestImage: clusterBuilderIdentifier,
+					}
+
+					rt.Test(rtesting.TableRow{
+						Key:     clusterBuilderKey,
+						Objects: []runtime.Object{clusterBuilder},
+						WantErr: false,
+						WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+							{
+								Object: &v1alpha1.ClusterBuilder{
+									ObjectMeta: clusterBuilder.ObjectMeta,
+									Spec:       clusterBuilder.Spec,
+									Status: v1alpha1.BuilderStatus{
+										Status: duckv1alpha1.Status{
+											ObservedGeneration: 1,
+											Conditions: duckv1alpha1.Conditions{
+												{
+													Type:   duckv1alpha1.ConditionReady,
+													Status: corev1.ConditionTrue,
+												},
+												{
+													Type:    v1alpha1.ConditionUpToDate,
+													Status:  corev1.ConditionFalse,
+													Reason:  v1alpha1.BuilderRefreshFailed,
+													Message: "unavailable metadata",
+												},
+											},
+										},
+										BuilderMetadata: []v1alpha1.BuildpackMetadata{
+											{
+												ID:      "buildpack.version",
+												Version: "version",
+											},
+										},
+										LatestImage: clusterBuilderIdentifier,
+									},
+								},
+							},
+						},
+					})
+				})
 			})
 		})
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/controller"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -17,19 +18,20 @@ import (
 	k8sclient "k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
 	v1alpha1informers "github.com/pivotal/kpack/pkg/client/informers/externalversions/build/v1alpha1"
 	v1alpha1Listers "github.com/pivotal/kpack/pkg/client/listers/build/v1alpha1"
+	"github.com/pivotal/kpack/pkg/config"
 	"github.com/pivotal/kpack/pkg/reconciler"
 	"github.com/pivotal/kpack/pkg/tracker"
 )
 
 const (
-	ReconcilerName           = "Images"
-	Kind                     = "Image"
-	buildHistoryDefaultLimit = 10
+	ReconcilerName = "Images"
+	Kind           = "Image"
 )
 
 type Tracker interface {
@@ -48,6 +50,8 @@ func NewController(opt reconciler.Options,
 	c := &Reconciler{
 		Client:               opt.Client,
 		K8sClient:            k8sClient,
+		Recorder:             opt.Recorder,
+		ConfigStore:          opt.ConfigStore,
 		ImageLister:          imageInformer.Lister(),
 		BuildLister:          buildInformer.Lister(),
 		BuilderLister:        builderInformer.Lister(),
@@ -100,6 +104,8 @@ type Reconciler struct {
 	PvcLister            corelisters.PersistentVolumeClaimLister
 	Tracker              Tracker
 	K8sClient            k8sclient.Interface
+	Recorder             record.EventRecorder
+	ConfigStore          *config.Store
 }
 
 func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
@@ -147,11 +153,19 @@ func (c *Reconciler) reconcileImage(image *v1alpha1.Image) (*v1alpha1.Image, err
 		return image, nil
 	}
 
-	image.Status.BuildCacheName, err = c.reconcileBuildCache(image)
+	buildCache, err := c.reconcileBuildCache(image)
 	if err != nil {
 		return nil, err
 	}
 
+	if buildCache.recreating {
+		image.Status.BuildCacheName = ""
+		image.Status.Conditions = image.BuildCacheRecreating()
+		image.Status.ObservedGeneration = image.Generation
+		return image, nil
+	}
+	image.Status.BuildCacheName = buildCache.name
+
 	sourceResolver, err := c.reconcileSourceResolver(image)
 	if err != nil {
 		return nil, err
@@ -167,10 +181,14 @@ func (c *Reconciler) reconcileImage(image *v1alpha1.Image) (*v1alpha1.Image, err
 		return nil, err
 	}
 
+	if reconciledBuild.Build != nil && reconciledBuild.Build != lastBuild {
+		c.Recorder.Eventf(image, corev1.EventTypeNormal, "BuildCreated", "Created build %s with reason %s", reconciledBuild.Build.Name, reconciledBuild.Build.Annotations[v1alpha1.BuildReasonAnnotation])
+	}
+
 	image.Status.LatestBuildRef = reconciledBuild.Build.BuildRef()
 	image.Status.BuildCounter = reconciledBuild.BuildCounter
 	image.Status.LatestImage = reconciledBuild.LatestImage
-	image.Status.Conditions = reconciledBuild.Conditions
+	image.Status.Conditions = append(reconciledBuild.Conditions, buildCache.conditions...)
 	image.Status.ObservedGeneration = image.Generation
 
 	return image, c.deleteOldBuilds(image)
@@ -216,41 +234,86 @@ func (c *Reconciler) reconcileSourceResolver(image *v1alpha1.Image) (*v1alpha1.S
 	return c.Client.BuildV1alpha1().SourceResolvers(image.Namespace).Update(sourceResolver)
 }
 
-func (c *Reconciler) reconcileBuildCache(image *v1alpha1.Image) (string, error) {
+type buildCacheStatus struct {
+	name       string
+	conditions duckv1alpha1.Conditions
+	recreating bool
+}
+
+// reconcileBuildCache is only called between builds so the cache can be safely deleted or recreated.
+// Builds are not scheduled while a cache is being recreated.
+func (c *Reconciler) reconcileBuildCache(image *v1alpha1.Image) (buildCacheStatus, error) {
+	image = c.withDefaultCacheSize(image)
+
 	if !image.NeedCache() {
 		buildCache, err := c.PvcLister.PersistentVolumeClaims(image.Namespace).Get(image.CacheName())
 		if err != nil && !k8serrors.IsNotFound(err) {
-			return "", errors.Wrap(err, "cannot retrieve persistent volume claim")
+			return buildCacheStatus{}, errors.Wrap(err, "cannot retrieve persistent volume claim")
 		} else if k8serrors.IsNotFound(err) {
-			return "", nil
+			return buildCacheStatus{}, nil
 		}
 
-		return "", c.K8sClient.CoreV1().PersistentVolumeClaims(image.Namespace).Delete(image.CacheName(), &metav1.DeleteOptions{
-			Preconditions: &metav1.Preconditions{UID: &buildCache.UID},
-		})
+		return buildCacheStatus{}, c.deleteBuildCache(buildCache)
 	}
 
 	desiredBuildCache := image.BuildCache()
 
 	buildCache, err := c.PvcLister.PersistentVolumeClaims(image.Namespace).Get(image.CacheName())
 	if err != nil && !k8serrors.IsNotFound(err) {
-		return "", fmt.Errorf("failed to get image cache: %s", err)
+		return buildCacheStatus{}, fmt.Errorf("failed to get image cache: %s", err)
 	} else if k8serrors.IsNotFound(err) {
 		buildCache, err = c.K8sClient.CoreV1().PersistentVolumeClaims(image.Namespace).Create(desiredBuildCache)
 		if err != nil {
-			return "", fmt.Errorf("failed creating image cache for build: %s", err)
+			return buildCacheStatus{}, fmt.Errorf("failed creating image cache for build: %s", err)
+		}
+	}
+
+	if buildCache.DeletionTimestamp != nil {
+		return buildCacheStatus{recreating: true}, nil
+	}
+
+	if image.BuildCacheRecreateNeeded(buildCache) {
+		err := c.deleteBuildCache(buildCache)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return buildCacheStatus{}, err
 		}
+		return buildCacheStatus{recreating: true}, nil
 	}
 
 	if buildCacheEqual(desiredBuildCache, buildCache) {
-		return buildCache.Name, nil
+		return buildCacheStatus{name: buildCache.Name, conditions: image.BuildCacheConditions(buildCache)}, nil
 	}
 
 	existing := buildCache.DeepCopy()
 	existing.Spec.Resources = desiredBuildCache.Spec.Resources
 	existing.ObjectMeta.Labels = desiredBuildCache.ObjectMeta.Labels
-	_, err = c.K8sClient.CoreV1().PersistentVolumeClaims(image.Namespace).Update(existing)
-	return existing.Name, errors.Wrap(err, "cannot update persistent volume claim")
+	updated, err := c.K8sClient.CoreV1().PersistentVolumeClaims(image.Namespace).Update(existing)
+	if k8serrors.IsInvalid(err) || k8serrors.IsForbidden(err) {
+		return buildCacheStatus{name: buildCache.Name, conditions: image.BuildCacheResizeFailed(err)}, nil
+	} else if err != nil {
+		return buildCacheStatus{}, errors.Wrap(err, "cannot update persistent volume claim")
+	}
+
+	return buildCacheStatus{name: updated.Name, conditions: image.BuildCacheConditions(updated)}, nil
+}
+
+// withDefaultCacheSize gives images without a cache size or cache image the configured default cache size.
+// The default is only applied to the build cache, it is never written back to the image spec.
+func (c *Reconciler) withDefaultCacheSize(image *v1alpha1.Image) *v1alpha1.Image {
+	defaultCacheSize := c.ConfigStore.Load().CacheSize
+	if defaultCacheSize == nil || image.Spec.CacheSize != nil || image.Spec.CacheImage != "" {
+		return image
+	}
+
+	image = image.DeepCopy()
+	image.Spec.CacheSize = defaultCacheSize
+	return image
+}
+
+func (c *Reconciler) deleteBuildCache(buildCache *corev1.PersistentVolumeClaim) error {
+	return c.K8sClient.CoreV1().PersistentVolumeClaims(buildCache.Namespace).Delete(buildCache.Name, &metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &buildCache.UID},
+	})
 }
 
 func (c *Reconciler) deleteOldBuilds(image *v1alpha1.Image) error {
@@ -259,6 +322,8 @@ func (c *Reconciler) deleteOldBuilds(image *v1alpha1.Image) error {
 		return fmt.Errorf("failed fetching all builds for image: %s", err)
 	}
 
+	buildHistoryDefaultLimit := c.ConfigStore.Load().BuildHistoryLimit
+
 	if builds.NumberFailedBuilds() > limitOrDefault(image.Spec.FailedBuildHistoryLimit, buildHistoryDefaultLimit) {
 		oldestFailedBuild := builds.OldestFailure()
 
@@ -266,6 +331,7 @@ func (c *Reconciler) deleteOldBuilds(image *v1alpha1.Image) error {
 		if err != nil {
 			return fmt.Errorf("failed deleting failed build: %s", err)
 		}
+		c.Recorder.Eventf(image, corev1.EventTypeNormal, "BuildDeleted", "Deleted failed build %s", oldestFailedBuild.Name)
 	}
 
 	if builds.NumberSuccessfulBuilds() > limitOrDefault(image.Spec.SuccessBuildHistoryLimit, buildHistoryDefaultLimit) {
@@ -275,6 +341,7 @@ func (c *Reconciler) deleteOldBuilds(image *v1alpha1.Image) error {
 		if err != nil {
 			return fmt.Errorf("failed deleting successful build: %s", err)
 		}
+		c.Recorder.Eventf(image, corev1.EventTypeNormal, "BuildDeleted", "Deleted successful build %s", oldestSuccess.Name)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package image_test
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -12,7 +13,9 @@ import (
 	rtesting "github.com/knative/pkg/reconciler/testing"
 	"github.com/sclevine/spec"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,6 +27,7 @@ import (
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/pivotal/kpack/pkg/config"
 	"github.com/pivotal/kpack/pkg/reconciler/testhelpers"
 	"github.com/pivotal/kpack/pkg/reconciler/v1alpha1/image"
 )
@@ -47,6 +51,7 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 	)
 	var (
 		fakeTracker = fakeTracker{}
+		configStore = config.NewStore(zap.NewNop().Sugar(), config.Default())
 	)
 
 	rt := testhelpers.ReconcilerTester(t,
@@ -55,6 +60,9 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 
 			fakeClient := fake.NewSimpleClientset(listers.BuildServiceObjects()...)
 			k8sfakeClient := k8sfake.NewSimpleClientset(listers.GetKubeObjects()...)
+			for _, reactor := range row.WithReactors {
+				k8sfakeClient.PrependReactor("*", "*", reactor)
+			}
 
 			eventRecorder := record.NewFakeRecorder(10)
 			actionRecorderList := rtesting.ActionRecorderList{fakeClient, k8sfakeClient}
@@ -70,6 +78,8 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 				PvcLister:            listers.GetPersistentVolumeClaimLister(),
 				Tracker:              fakeTracker,
 				K8sClient:            k8sfakeClient,
+				Recorder:             eventRecorder,
+				ConfigStore:          configStore,
 			}
 
 			rtesting.PrependGenerateNameReactor(&fakeClient.Fake)
@@ -440,6 +450,63 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			it("creates a cache with the configured default size if no cache is requested", func() {
+				defaultCacheSize := resource.MustParse("2G")
+				configStore.OnConfigChanged(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: config.ConfigName},
+					Data: map[string]string{
+						"defaultCacheSize": "2G",
+					},
+				})
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						image,
+						image.SourceResolver(),
+						builder,
+					},
+					WantErr: false,
+					WantCreates: []runtime.Object{
+						&corev1.PersistentVolumeClaim{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      image.CacheName(),
+								Namespace: namespace,
+								OwnerReferences: []metav1.OwnerReference{
+									*kmeta.NewControllerRef(image),
+								},
+								Labels: map[string]string{
+									someLabelKey: someValueToPassThrough,
+								},
+							},
+							Spec: corev1.PersistentVolumeClaimSpec{
+								AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceStorage: defaultCacheSize,
+									},
+								},
+							},
+						},
+					},
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Image{
+								ObjectMeta: image.ObjectMeta,
+								Spec:       image.Spec,
+								Status: v1alpha1.ImageStatus{
+									BuildCacheName: image.CacheName(),
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions:         conditionReadyUnknown(),
+									},
+								},
+							},
+						},
+					},
+				})
+			})
+
 			it("does not create a cache if a cache already exists", func() {
 				image.Spec.CacheSize = &cacheSize
 				image.Status.BuildCacheName = image.CacheName()
@@ -560,6 +627,247 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			it("recreates the build cache when it shrinks", func() {
+				largerCacheSize := resource.MustParse("2.5")
+				image.Spec.CacheSize = &largerCacheSize
+				image.Status.BuildCacheName = image.CacheName()
+				largerCache := image.BuildCache()
+				image.Spec.CacheSize = &cacheSize
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						image,
+						resolvedSourceResolver(image),
+						builder,
+						largerCache,
+					},
+					WantErr: false,
+					WantDeletes: []clientgotesting.DeleteActionImpl{
+						{
+							Name: image.CacheName(),
+						},
+					},
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Image{
+								ObjectMeta: image.ObjectMeta,
+								Spec:       image.Spec,
+								Status: v1alpha1.ImageStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions:         append(conditionReadyUnknown(), conditionCacheRecreating(image)),
+									},
+								},
+							},
+						},
+					},
+				})
+			})
+
+			it("recreates the build cache when the storage class changes", func() {
+				image.Spec.CacheSize = &cacheSize
+				image.Status.BuildCacheName = image.CacheName()
+				existingCache := image.BuildCache()
+
+				storageClassImage := image.DeepCopy()
+				storageClassImage.Spec.CacheStorageClassName = "some-storage-class"
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						storageClassImage,
+						resolvedSourceResolver(storageClassImage),
+						builder,
+						existingCache,
+					},
+					WantErr: false,
+					WantDeletes: []clientgotesting.DeleteActionImpl{
+						{
+							Name: image.CacheName(),
+						},
+					},
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Image{
+								ObjectMeta: storageClassImage.ObjectMeta,
+								Spec:       storageClassImage.Spec,
+								Status: v1alpha1.ImageStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions:         append(conditionReadyUnknown(), conditionCacheRecreating(image)),
+									},
+								},
+							},
+						},
+					},
+				})
+			})
+
+			it("recreates the build cache when the reset annotation changes", func() {
+				image.Spec.CacheSize = &cacheSize
+				image.Status.BuildCacheName = image.CacheName()
+				existingCache := image.BuildCache()
+
+				resetImage := image.DeepCopy()
+				resetImage.Annotations = map[string]string{
+					v1alpha1.CacheResetAnnotation: "2019-10-01",
+				}
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						resetImage,
+						resolvedSourceResolver(resetImage),
+						builder,
+						existingCache,
+					},
+					WantErr: false,
+					WantDeletes: []clientgotesting.DeleteActionImpl{
+						{
+							Name: image.CacheName(),
+						},
+					},
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Image{
+								ObjectMeta: resetImage.ObjectMeta,
+								Spec:       resetImage.Spec,
+								Status: v1alpha1.ImageStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions:         append(conditionReadyUnknown(), conditionCacheRecreating(image)),
+									},
+								},
+							},
+						},
+					},
+				})
+			})
+
+			it("does not schedule builds while the build cache is being deleted", func() {
+				image.Spec.CacheSize = &cacheSize
+				image.Status.BuildCacheName = image.CacheName()
+				deletingCache := image.BuildCache()
+				deletingCache.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						image,
+						resolvedSourceResolver(image),
+						builder,
+						deletingCache,
+					},
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Image{
+								ObjectMeta: image.ObjectMeta,
+								Spec:       image.Spec,
+								Status: v1alpha1.ImageStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions:         append(conditionReadyUnknown(), conditionCacheRecreating(image)),
+									},
+								},
+							},
+						},
+					},
+				})
+			})
+
+			it("reports a pending build cache", func() {
+				image.Spec.CacheSize = &cacheSize
+				image.Status.BuildCacheName = image.CacheName()
+				pendingCache := image.BuildCache()
+				pendingCache.Status.Phase = corev1.ClaimPending
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						image,
+						image.SourceResolver(),
+						builder,
+						pendingCache,
+					},
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Image{
+								ObjectMeta: image.ObjectMeta,
+								Spec:       image.Spec,
+								Status: v1alpha1.ImageStatus{
+									BuildCacheName: image.CacheName(),
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: append(conditionReadyUnknown(), duckv1alpha1.Condition{
+											Type:    v1alpha1.ConditionCacheReady,
+											Status:  corev1.ConditionFalse,
+											Reason:  v1alpha1.CachePending,
+											Message: "Build cache image-name-cache is pending.",
+										}),
+									},
+								},
+							},
+						},
+					},
+				})
+			})
+
+			it("reports a build cache that cannot be resized", func() {
+				image.Spec.CacheSize = &cacheSize
+				image.Status.BuildCacheName = image.CacheName()
+				existingCache := image.BuildCache()
+				largerCacheSize := resource.MustParse("2.5")
+				image.Spec.CacheSize = &largerCacheSize
+				resizedCache := image.BuildCache()
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						image,
+						image.SourceResolver(),
+						builder,
+						existingCache,
+					},
+					WithReactors: []clientgotesting.ReactionFunc{
+						func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+							if !action.Matches("update", "persistentvolumeclaims") {
+								return false, nil, nil
+							}
+							return true, nil, k8serrors.NewForbidden(schema.GroupResource{Resource: "persistentvolumeclaims"}, image.CacheName(), errors.New("storage class does not allow volume expansion"))
+						},
+					},
+					WantErr: false,
+					WantUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: resizedCache,
+						},
+					},
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Image{
+								ObjectMeta: image.ObjectMeta,
+								Spec:       image.Spec,
+								Status: v1alpha1.ImageStatus{
+									BuildCacheName: image.CacheName(),
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: append(conditionReadyUnknown(), duckv1alpha1.Condition{
+											Type:    v1alpha1.ConditionCacheReady,
+											Status:  corev1.ConditionFalse,
+											Reason:  v1alpha1.CacheResizeFailed,
+											Message: `Unable to resize build cache image-name-cache: persistentvolumeclaims "image-name-cache" is forbidden: storage class does not allow volume expansion`,
+										}),
+									},
+								},
+							},
+						},
+					},
+				})
+			})
+
 			it("deletes a cache if already exists and not requested", func() {
 				image.Status.BuildCacheName = image.CacheName()
 				image.Spec.CacheSize = nil
@@ -614,6 +922,50 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			it("reports source resolution failures on the image", func() {
+				sourceResolver := unresolvedSourceResolver(image)
+				sourceResolver.ResolveFailed(errors.New("unable to fetch references"))
+
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: []runtime.Object{
+						image,
+						builder,
+						sourceResolver,
+					},
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Image{
+								ObjectMeta: image.ObjectMeta,
+								Spec:       image.Spec,
+								Status: v1alpha1.ImageStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:   duckv1alpha1.ConditionReady,
+												Status: corev1.ConditionUnknown,
+											},
+											{
+												Type:   v1alpha1.ConditionBuilderReady,
+												Status: corev1.ConditionTrue,
+											},
+											{
+												Type:    v1alpha1.ConditionSourceResolved,
+												Status:  corev1.ConditionFalse,
+												Reason:  v1alpha1.SourceResolutionFailed,
+												Message: "unable to fetch references",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				})
+			})
+
 			it("does not schedule a build if the builder is not ready", func() {
 				rt.Test(rtesting.TableRow{
 					Key: key,
@@ -707,6 +1059,9 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 							},
 						},
 					},
+					WantEvents: []string{
+						"Normal BuildCreated Created build image-name-build-1-00001 with reason CONFIG",
+					},
 				})
 			})
 
@@ -782,6 +1137,9 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 							},
 						},
 					},
+					WantEvents: []string{
+						"Normal BuildCreated Created build image-name-build-1-00001 with reason CONFIG",
+					},
 				})
 			})
 
@@ -880,6 +1238,9 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 							},
 						},
 					},
+					WantEvents: []string{
+						"Normal BuildCreated Created build image-name-build-2-00001 with reason CONFIG,COMMIT",
+					},
 				})
 			})
 
@@ -989,6 +1350,9 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 							},
 						},
 					},
+					WantEvents: []string{
+						"Normal BuildCreated Created build image-name-build-2-00001 with reason COMMIT",
+					},
 				})
 			})
 
@@ -1124,6 +1488,9 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 							},
 						},
 					},
+					WantEvents: []string{
+						"Normal BuildCreated Created build image-name-build-2-00001 with reason BUILDPACK",
+					},
 				})
 			})
 
@@ -1264,6 +1631,47 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 								Name: image.Name + "-build-1", // first-build
 							},
 						},
+						WantEvents: []string{
+							"Normal BuildDeleted Deleted failed build image-name-build-1",
+						},
+					})
+				})
+
+				it("deletes a failed build if more than the configured default limit", func() {
+					configStore.OnConfigChanged(&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Name: config.ConfigName},
+						Data: map[string]string{
+							"buildHistoryLimit": "4",
+						},
+					})
+					image.Status.LatestBuildRef = "image-name-build-5"
+					image.Status.Conditions = conditionNotReady()
+					image.Status.BuildCounter = 5
+					sourceResolver := resolvedSourceResolver(image)
+
+					rt.Test(rtesting.TableRow{
+						Key: key,
+						Objects: runtimeObjects(
+							failedBuilds(image, sourceResolver, 5),
+							image,
+							builder,
+							sourceResolver,
+						),
+						WantErr: false,
+						WantDeletes: []clientgotesting.DeleteActionImpl{
+							{
+								ActionImpl: clientgotesting.ActionImpl{
+									Namespace:   "blah",
+									Verb:        "",
+									Resource:    schema.GroupVersionResource{},
+									Subresource: "",
+								},
+								Name: image.Name + "-build-1", // first-build
+							},
+						},
+						WantEvents: []string{
+							"Normal BuildDeleted Deleted failed build image-name-build-1",
+						},
 					})
 				})
 
@@ -1295,6 +1703,9 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 								Name: image.Name + "-build-1", // first-build
 							},
 						},
+						WantEvents: []string{
+							"Normal BuildDeleted Deleted successful build image-name-build-1",
+						},
 					})
 				})
 			})
@@ -1342,6 +1753,57 @@ func testImageReconciler(t *testing.T, when spec.G, it spec.S) {
 					},
 				})
 			})
+
+			it("carries the failure of the last build into the image ready condition", func() {
+				image.Status.BuildCounter = 1
+				image.Status.LatestBuildRef = "image-name-build-1"
+				image.Status.LatestImage = "some/image@some-old-sha"
+
+				sourceResolver := resolvedSourceResolver(image)
+				rt.Test(rtesting.TableRow{
+					Key: key,
+					Objects: runtimeObjects(
+						builds(image, sourceResolver, 1, duckv1alpha1.Condition{
+							Type:    duckv1alpha1.ConditionSucceeded,
+							Status:  corev1.ConditionFalse,
+							Reason:  "DetectFailed",
+							Message: "Step detect failed with exit code 1: no buildpacks participating",
+						}),
+						image,
+						builder,
+						sourceResolver,
+					),
+					WantErr: false,
+					WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+						{
+							Object: &v1alpha1.Image{
+								ObjectMeta: image.ObjectMeta,
+								Spec:       image.Spec,
+								Status: v1alpha1.ImageStatus{
+									Status: duckv1alpha1.Status{
+										ObservedGeneration: originalGeneration,
+										Conditions: duckv1alpha1.Conditions{
+											{
+												Type:    duckv1alpha1.ConditionReady,
+												Status:  corev1.ConditionFalse,
+												Reason:  "DetectFailed",
+												Message: "Step detect failed with exit code 1: no buildpacks participating",
+											},
+											{
+												Type:   v1alpha1.ConditionBuilderReady,
+												Status: corev1.ConditionTrue,
+											},
+										},
+									},
+									LatestBuildRef: "image-name-build-1",
+									LatestImage:    "some/image@some-old-sha",
+									BuildCounter:   1,
+								},
+							},
+						},
+					},
+				})
+			})
 		})
 	})
 }
@@ -1433,6 +1895,15 @@ func limit(limit int64) *int64 {
 	return &limit
 }
 
+func conditionCacheRecreating(image *v1alpha1.Image) duckv1alpha1.Condition {
+	return duckv1alpha1.Condition{
+		Type:    v1alpha1.ConditionCacheReady,
+		Status:  corev1.ConditionUnknown,
+		Reason:  v1alpha1.CacheRecreating,
+		Message: fmt.Sprintf("Build cache %s is being recreated.", image.CacheName()),
+	}
+}
+
 func conditionReadyUnknown() duckv1alpha1.Conditions {
 	return duckv1alpha1.Conditions{
 		{
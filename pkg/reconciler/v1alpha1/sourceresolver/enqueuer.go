@@ -7,10 +7,10 @@ import (
 
 type workQueueEnqueuer struct {
 	enqueueAfter func(obj interface{}, after time.Duration)
-	delay        time.Duration
+	delay        func() time.Duration
 }
 
 func (e *workQueueEnqueuer) Enqueue(sr *v1alpha1.SourceResolver) error {
-	e.enqueueAfter(sr, 1*time.Minute)
+	e.enqueueAfter(sr, e.delay())
 	return nil
 }
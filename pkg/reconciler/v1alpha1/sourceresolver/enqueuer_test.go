@@ -16,7 +16,9 @@ func TestEnqueueAfter(t *testing.T) {
 	}
 
 	enqueuer := &workQueueEnqueuer{
-		delay: time.Minute,
+		delay: func() time.Duration {
+			return time.Minute
+		},
 		enqueueAfter: func(obj interface{}, after time.Duration) {
 			require.Equal(t, sourceResolver, obj)
 			require.Equal(t, after, time.Minute)
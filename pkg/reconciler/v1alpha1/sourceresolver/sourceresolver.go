@@ -3,16 +3,20 @@ package sourceresolver
 import (
 	"context"
 	"errors"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/knative/pkg/controller"
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
 	v1alpha1informers "github.com/pivotal/kpack/pkg/client/informers/externalversions/build/v1alpha1"
 	v1alpha1listers "github.com/pivotal/kpack/pkg/client/listers/build/v1alpha1"
+	"github.com/pivotal/kpack/pkg/metrics"
 	"github.com/pivotal/kpack/pkg/reconciler"
 )
 
@@ -39,6 +43,7 @@ func NewController(
 		BlobResolver:         blobResolver,
 		RegistryResolver:     registryResolver,
 		Client:               opt.Client,
+		Recorder:             opt.Recorder,
 		SourceResolverLister: sourceResolverInformer.Lister(),
 	}
 
@@ -46,7 +51,9 @@ func NewController(
 
 	c.Enqueuer = &workQueueEnqueuer{
 		enqueueAfter: impl.EnqueueAfter,
-		delay:        opt.SourcePollingFrequency,
+		delay: func() time.Duration {
+			return opt.ConfigStore.Load().SourcePollingFrequency
+		},
 	}
 
 	sourceResolverInformer.Informer().AddEventHandler(reconciler.Handler(impl.Enqueue))
@@ -65,6 +72,7 @@ type Reconciler struct {
 	RegistryResolver     Resolver
 	Enqueuer             Enqueuer
 	Client               versioned.Interface
+	Recorder             record.EventRecorder
 	SourceResolverLister v1alpha1listers.SourceResolverLister
 }
 
@@ -87,11 +95,19 @@ func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
 		return err
 	}
 
+	started := time.Now()
 	resolvedSource, err := sourceReconciler.Resolve(sourceResolver)
+	metrics.SourceResolved(sourceType(sourceResolver), started, err)
 	if err != nil {
+		sourceResolver.ResolveFailed(err)
+		sourceResolver.Status.ObservedGeneration = sourceResolver.Generation
+		if updateErr := c.updateStatus(sourceResolver); updateErr != nil {
+			return updateErr
+		}
 		return err
 	}
 
+	previousRevision := revision(sourceResolver.Status.Source)
 	sourceResolver.ResolvedSource(resolvedSource)
 
 	if sourceResolver.PollingReady() {
@@ -102,7 +118,18 @@ func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
 	}
 
 	sourceResolver.Status.ObservedGeneration = sourceResolver.Generation
-	return c.updateStatus(sourceResolver)
+	err = c.updateStatus(sourceResolver)
+	if err != nil {
+		return err
+	}
+
+	if currentRevision := revision(sourceResolver.Status.Source); previousRevision != "" && currentRevision != previousRevision {
+		c.Recorder.Eventf(sourceResolver, corev1.EventTypeNormal, "RevisionChanged", "Source revision changed from %s to %s", previousRevision, currentRevision)
+		if image := reconciler.OwningImage(sourceResolver); image != nil {
+			c.Recorder.Eventf(image, corev1.EventTypeNormal, "RevisionChanged", "Source revision changed from %s to %s", previousRevision, currentRevision)
+		}
+	}
+	return nil
 }
 
 func (c *Reconciler) sourceReconciler(sourceResolver *v1alpha1.SourceResolver) (Resolver, error) {
@@ -116,6 +143,30 @@ func (c *Reconciler) sourceReconciler(sourceResolver *v1alpha1.SourceResolver) (
 	return nil, errors.New("invalid source type")
 }
 
+func revision(source v1alpha1.ResolvedSourceConfig) string {
+	switch {
+	case source.Git != nil:
+		return source.Git.Revision
+	case source.Blob != nil:
+		return source.Blob.Revision
+	case source.Registry != nil:
+		return source.Registry.Digest
+	default:
+		return ""
+	}
+}
+
+func sourceType(sourceResolver *v1alpha1.SourceResolver) string {
+	switch {
+	case sourceResolver.IsGit():
+		return "git"
+	case sourceResolver.IsBlob():
+		return "blob"
+	default:
+		return "registry"
+	}
+}
+
 func (c *Reconciler) updateStatus(desired *v1alpha1.SourceResolver) error {
 	original, err := c.SourceResolverLister.SourceResolvers(desired.Namespace).Get(desired.Name)
 	if err != nil {
@@ -1,10 +1,12 @@
 package sourceresolver_test
 
 import (
+	"errors"
 	"testing"
 
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/controller"
+	"github.com/knative/pkg/kmeta"
 	rtesting "github.com/knative/pkg/reconciler/testing"
 	"github.com/sclevine/spec"
 	"github.com/stretchr/testify/require"
@@ -57,6 +59,7 @@ func testSourceResolver(t *testing.T, when spec.G, it spec.S) {
 				RegistryResolver:     fakeRegistryResolver,
 				Enqueuer:             fakeEnqueuer,
 				Client:               fakeClient,
+				Recorder:             eventRecorder,
 				SourceResolverLister: listers.GetSourceResolverLister(),
 			}
 
@@ -171,6 +174,10 @@ func testSourceResolver(t *testing.T, when spec.G, it spec.S) {
 													Type:   v1alpha1.ActivePolling,
 													Status: corev1.ConditionTrue,
 												},
+												{
+													Type:   v1alpha1.ConditionSourceResolved,
+													Status: corev1.ConditionTrue,
+												},
 											},
 										},
 										Source: v1alpha1.ResolvedSourceConfig{
@@ -191,6 +198,84 @@ func testSourceResolver(t *testing.T, when spec.G, it spec.S) {
 					require.Equal(t, sourceResolver.Name, enquedSourceResolver.Name)
 					require.Equal(t, sourceResolver.Namespace, enquedSourceResolver.Namespace)
 				})
+
+				it("records an event when the revision changes", func() {
+					sourceResolver := resolvedSourceResolver(sourceResolver.DeepCopy(), v1alpha1.ResolvedSourceConfig{
+						Git: &v1alpha1.ResolvedGitSource{
+							URL:      "https://example.com/something",
+							Revision: "previous-sha",
+							Type:     v1alpha1.Branch,
+						},
+					})
+
+					rt.Test(rtesting.TableRow{
+						Key: key,
+						Objects: []runtime.Object{
+							sourceResolver,
+						},
+						WantErr: false,
+						WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+							{
+								Object: &v1alpha1.SourceResolver{
+									ObjectMeta: sourceResolver.ObjectMeta,
+									Spec:       sourceResolver.Spec,
+									Status: v1alpha1.SourceResolverStatus{
+										Status: duckv1alpha1.Status{
+											ObservedGeneration: originalGeneration,
+											Conditions:         sourceResolver.Status.Conditions,
+										},
+										Source: resolvedSource,
+									},
+								},
+							},
+						},
+						WantEvents: []string{
+							"Normal RevisionChanged Source revision changed from previous-sha to abcdef",
+						},
+					})
+				})
+
+				it("also records the revision change on the owning image", func() {
+					sourceResolver := resolvedSourceResolver(sourceResolver.DeepCopy(), v1alpha1.ResolvedSourceConfig{
+						Git: &v1alpha1.ResolvedGitSource{
+							URL:      "https://example.com/something",
+							Revision: "previous-sha",
+							Type:     v1alpha1.Branch,
+						},
+					})
+					sourceResolver.OwnerReferences = []v1.OwnerReference{
+						*kmeta.NewControllerRef(&v1alpha1.Image{
+							ObjectMeta: v1.ObjectMeta{Name: "some-image", Namespace: namespace},
+						}),
+					}
+
+					rt.Test(rtesting.TableRow{
+						Key: key,
+						Objects: []runtime.Object{
+							sourceResolver,
+						},
+						WantErr: false,
+						WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+							{
+								Object: &v1alpha1.SourceResolver{
+									ObjectMeta: sourceResolver.ObjectMeta,
+									Spec:       sourceResolver.Spec,
+									Status: v1alpha1.SourceResolverStatus{
+										Status: duckv1alpha1.Status{
+											ObservedGeneration: originalGeneration,
+											Conditions:         sourceResolver.Status.Conditions,
+										},
+										Source: resolvedSource,
+									},
+								},
+							},
+						},
+						WantEvents: []string{
+							"Normal RevisionChanged Source revision changed from previous-sha to abcdef",
+							"Normal RevisionChanged Source revision changed from previous-sha to abcdef",
+						},
+					})
+				})
 			})
 
 			when("a specific commit sha is the source", func() {
@@ -229,6 +314,10 @@ func testSourceResolver(t *testing.T, when spec.G, it spec.S) {
 													Type:   v1alpha1.ActivePolling,
 													Status: corev1.ConditionFalse,
 												},
+												{
+													Type:   v1alpha1.ConditionSourceResolved,
+													Status: corev1.ConditionTrue,
+												},
 											},
 										},
 										Source: v1alpha1.ResolvedSourceConfig{
@@ -286,6 +375,10 @@ func testSourceResolver(t *testing.T, when spec.G, it spec.S) {
 													Type:   v1alpha1.ActivePolling,
 													Status: corev1.ConditionFalse,
 												},
+												{
+													Type:   v1alpha1.ConditionSourceResolved,
+													Status: corev1.ConditionTrue,
+												},
 											},
 										},
 										Source: v1alpha1.ResolvedSourceConfig{
@@ -322,6 +415,99 @@ func testSourceResolver(t *testing.T, when spec.G, it spec.S) {
 					})
 				})
 			})
+			when("git fails to resolve", func() {
+				fakeGitResolver.ResolveReturns(v1alpha1.ResolvedSourceConfig{}, errors.New("unable to fetch references"))
+				fakeGitResolver.CanResolveReturns(true)
+
+				it("reports the failure when source has not previously resolved", func() {
+					rt.Test(rtesting.TableRow{
+						Key: key,
+						Objects: []runtime.Object{
+							sourceResolver,
+						},
+						WantErr: true,
+						WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+							{
+								Object: &v1alpha1.SourceResolver{
+									ObjectMeta: sourceResolver.ObjectMeta,
+									Spec:       sourceResolver.Spec,
+									Status: v1alpha1.SourceResolverStatus{
+										Status: duckv1alpha1.Status{
+											ObservedGeneration: originalGeneration,
+											Conditions: duckv1alpha1.Conditions{
+												{
+													Type:    duckv1alpha1.ConditionReady,
+													Status:  corev1.ConditionFalse,
+													Reason:  v1alpha1.SourceResolutionFailed,
+													Message: "unable to fetch references",
+												},
+												{
+													Type:    v1alpha1.ConditionSourceResolved,
+													Status:  corev1.ConditionFalse,
+													Reason:  v1alpha1.SourceResolutionFailed,
+													Message: "unable to fetch references",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					})
+
+					require.Equal(t, 0, fakeEnqueuer.EnqueueCallCount())
+				})
+
+				it("keeps the resolved source ready when source has been previously resolved", func() {
+					alreadyResolvedSource := v1alpha1.ResolvedSourceConfig{
+						Git: &v1alpha1.ResolvedGitSource{
+							URL:      "https://example.com/something",
+							Revision: "abcdef",
+							Type:     v1alpha1.Branch,
+						},
+					}
+
+					alreadyResolvedSourceResolver := resolvedSourceResolver(sourceResolver.DeepCopy(), alreadyResolvedSource)
+
+					rt.Test(rtesting.TableRow{
+						Key: key,
+						Objects: []runtime.Object{
+							alreadyResolvedSourceResolver,
+						},
+						WantErr: true,
+						WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+							{
+								Object: &v1alpha1.SourceResolver{
+									ObjectMeta: sourceResolver.ObjectMeta,
+									Spec:       sourceResolver.Spec,
+									Status: v1alpha1.SourceResolverStatus{
+										Status: duckv1alpha1.Status{
+											ObservedGeneration: originalGeneration,
+											Conditions: duckv1alpha1.Conditions{
+												{
+													Type:   duckv1alpha1.ConditionReady,
+													Status: corev1.ConditionTrue,
+												},
+												{
+													Type:   v1alpha1.ActivePolling,
+													Status: corev1.ConditionTrue,
+												},
+												{
+													Type:    v1alpha1.ConditionSourceResolved,
+													Status:  corev1.ConditionFalse,
+													Reason:  v1alpha1.SourceResolutionFailed,
+													Message: "unable to fetch references",
+												},
+											},
+										},
+										Source: alreadyResolvedSource,
+									},
+								},
+							},
+						},
+					})
+				})
+			})
 		})
 
 		when("a blob based source config", func() {
@@ -374,6 +560,10 @@ func testSourceResolver(t *testing.T, when spec.G, it spec.S) {
 												Type:   v1alpha1.ActivePolling,
 												Status: corev1.ConditionFalse,
 											},
+											{
+												Type:   v1alpha1.ConditionSourceResolved,
+												Status: corev1.ConditionTrue,
+											},
 										},
 									},
 									Source: v1alpha1.ResolvedSourceConfig{
@@ -439,6 +629,10 @@ func testSourceResolver(t *testing.T, when spec.G, it spec.S) {
 												Type:   v1alpha1.ActivePolling,
 												Status: corev1.ConditionFalse,
 											},
+											{
+												Type:   v1alpha1.ConditionSourceResolved,
+												Status: corev1.ConditionTrue,
+											},
 										},
 									},
 									Source: v1alpha1.ResolvedSourceConfig{
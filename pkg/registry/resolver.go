@@ -1,15 +1,31 @@
 package registry
 
-import "github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+import (
+	"strings"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+)
 
 type Resolver struct {
+	RemoteImageFactory RemoteImageFactory
 }
 
-func (*Resolver) Resolve(sourceResolver *v1alpha1.SourceResolver) (v1alpha1.ResolvedSourceConfig, error) {
+func (r *Resolver) Resolve(sourceResolver *v1alpha1.SourceResolver) (v1alpha1.ResolvedSourceConfig, error) {
+	remoteImage, err := r.RemoteImageFactory.NewRemote(sourceImageRef{sourceResolver: sourceResolver})
+	if err != nil {
+		return v1alpha1.ResolvedSourceConfig{}, err
+	}
+
+	identifier, err := remoteImage.Identifier()
+	if err != nil {
+		return v1alpha1.ResolvedSourceConfig{}, err
+	}
+
 	return v1alpha1.ResolvedSourceConfig{
 		Registry: &v1alpha1.ResolvedRegistrySource{
 			Image:            sourceResolver.Spec.Source.Registry.Image,
 			ImagePullSecrets: sourceResolver.Spec.Source.Registry.ImagePullSecrets,
+			Digest:           identifier[strings.LastIndex(identifier, "@")+1:],
 			SubPath:          sourceResolver.Spec.Source.SubPath,
 		},
 	}, nil
@@ -18,3 +34,31 @@ func (*Resolver) Resolve(sourceResolver *v1alpha1.SourceResolver) (v1alpha1.Reso
 func (*Resolver) CanResolve(sourceResolver *v1alpha1.SourceResolver) bool {
 	return sourceResolver.IsRegistry()
 }
+
+// sourceImageRef authenticates with the image pull secrets of the source, like source-init does in the build
+type sourceImageRef struct {
+	sourceResolver *v1alpha1.SourceResolver
+}
+
+func (s sourceImageRef) ServiceAccount() string {
+	return ""
+}
+
+func (s sourceImageRef) Namespace() string {
+	return s.sourceResolver.Namespace
+}
+
+func (s sourceImageRef) Image() string {
+	return s.sourceResolver.Spec.Source.Registry.Image
+}
+
+func (s sourceImageRef) HasSecret() bool {
+	return len(s.sourceResolver.Spec.Source.Registry.ImagePullSecrets) > 0
+}
+
+func (s sourceImageRef) SecretName() string {
+	if s.HasSecret() {
+		return s.sourceResolver.Spec.Source.Registry.ImagePullSecrets[0].Name
+	}
+	return ""
+}
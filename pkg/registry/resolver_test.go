@@ -0,0 +1,100 @@
+package registry_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha1"
+	"github.com/pivotal/kpack/pkg/registry"
+	"github.com/pivotal/kpack/pkg/registry/registryfakes"
+)
+
+func TestRegistryResolver(t *testing.T) {
+	spec.Run(t, "Registry Resolver", testRegistryResolver)
+}
+
+func testRegistryResolver(t *testing.T, when spec.G, it spec.S) {
+	const digest = "sha256:f4d1d3fbd0a8e6b8f0d6a1d3f3b1c1a0e2b7e8d5c4b3a2918070605040302010"
+
+	var (
+		fakeFactory = &registryfakes.FakeRemoteImageFactory{}
+		resolver    = &registry.Resolver{RemoteImageFactory: fakeFactory}
+
+		sourceResolver = &v1alpha1.SourceResolver{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "some-source-resolver",
+				Namespace: "some-namespace",
+			},
+			Spec: v1alpha1.SourceResolverSpec{
+				ServiceAccount: "some-service-account",
+				Source: v1alpha1.SourceConfig{
+					Registry: &v1alpha1.Registry{
+						Image: "some-registry.io/some-image:latest",
+						ImagePullSecrets: []corev1.LocalObjectReference{
+							{Name: "some-pull-secret"},
+						},
+					},
+					SubPath: "some-path",
+				},
+			},
+		}
+	)
+
+	when("#Resolve", func() {
+		it("resolves the source image to a digest", func() {
+			fakeFactory.NewRemoteReturns(registryfakes.NewFakeRemoteImage("some-registry.io/some-image", digest), nil)
+
+			resolvedSource, err := resolver.Resolve(sourceResolver)
+			require.NoError(t, err)
+
+			assert.Equal(t, v1alpha1.ResolvedSourceConfig{
+				Registry: &v1alpha1.ResolvedRegistrySource{
+					Image: "some-registry.io/some-image:latest",
+					ImagePullSecrets: []corev1.LocalObjectReference{
+						{Name: "some-pull-secret"},
+					},
+					Digest:  digest,
+					SubPath: "some-path",
+				},
+			}, resolvedSource)
+			assert.True(t, resolvedSource.Registry.IsPollable())
+		})
+
+		it("uses the image pull secrets of the source", func() {
+			fakeFactory.NewRemoteReturns(registryfakes.NewFakeRemoteImage("some-registry.io/some-image", digest), nil)
+
+			_, err := resolver.Resolve(sourceResolver)
+			require.NoError(t, err)
+
+			imageRef := fakeFactory.NewRemoteArgsForCall(0)
+			assert.Equal(t, "some-registry.io/some-image:latest", imageRef.Image())
+			assert.Equal(t, "some-namespace", imageRef.Namespace())
+			assert.True(t, imageRef.HasSecret())
+			assert.Equal(t, "some-pull-secret", imageRef.SecretName())
+			assert.Equal(t, "", imageRef.ServiceAccount())
+		})
+
+		it("does not poll images referenced by digest", func() {
+			sourceResolver.Spec.Source.Registry.Image = "some-registry.io/some-image@" + digest
+			fakeFactory.NewRemoteReturns(registryfakes.NewFakeRemoteImage("some-registry.io/some-image", digest), nil)
+
+			resolvedSource, err := resolver.Resolve(sourceResolver)
+			require.NoError(t, err)
+
+			assert.False(t, resolvedSource.Registry.IsPollable())
+		})
+
+		it("returns an error when the image cannot be fetched", func() {
+			fakeFactory.NewRemoteReturns(nil, errors.New("unauthorized"))
+
+			_, err := resolver.Resolve(sourceResolver)
+			require.EqualError(t, err, "unauthorized")
+		})
+	})
+}
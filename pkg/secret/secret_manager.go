@@ -20,18 +20,23 @@ type SecretManager struct {
 type Matcher func(url, annotatedUrl string) bool
 
 func (m *SecretManager) SecretForServiceAccountAndURL(serviceAccount, namespace string, url string) (*URLAndUser, error) {
-	sa, err := m.Client.CoreV1().ServiceAccounts(namespace).Get(serviceAccount, meta_v1.GetOptions{})
+	secret, err := m.MatchingSecretForServiceAccount(serviceAccount, namespace, url)
 	if err != nil {
 		return nil, err
 	}
 
-	secret, err := m.secretForServiceAccount(sa, url, namespace)
+	registryUser := NewURLAndUser(url, string(secret.Data["username"]), string(secret.Data["password"]))
+	return &registryUser, nil
+}
+
+// MatchingSecretForServiceAccount returns the first secret of the service account annotated with a url matching url.
+func (m *SecretManager) MatchingSecretForServiceAccount(serviceAccount, namespace string, url string) (*v1.Secret, error) {
+	sa, err := m.Client.CoreV1().ServiceAccounts(namespace).Get(serviceAccount, meta_v1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	registryUser := NewURLAndUser(url, string(secret.Data["username"]), string(secret.Data["password"]))
-	return &registryUser, nil
+	return m.secretForServiceAccount(sa, url, namespace)
 }
 
 func (m *SecretManager) secretForServiceAccount(account *v1.ServiceAccount, url string, namespace string) (*v1.Secret, error) {